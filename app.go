@@ -7,9 +7,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -18,6 +21,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // App is the main application container that holds all configuration,
@@ -31,12 +35,32 @@ type App struct {
 	logger   *slog.Logger
 	server   *http.Server
 
+	// Infrastructure endpoints left out of request logs and traces
+	quietPaths map[string]bool
+
+	// Translators applied to errors returned by handlers
+	errorTranslators []ErrorTranslator
+
+	// Path of the error catalog page, linked from problem details
+	errorCatalogPath string
+
+	// Recovered panics
+	panics metric.Int64Counter
+
 	// Authorization
 	authzPolicy AuthzPolicy
 
+	// Shared store for per-operation rate limits
+	rateLimitStore atomic.Pointer[InMemoryRateLimitStore]
+	rateLimitOnce  sync.Once
+
 	// Lifecycle hooks
 	onStart []func(context.Context) error
 	onStop  []func(context.Context) error
+
+	// Lifecycle state reported by the startup and readiness probes
+	started  atomic.Bool
+	draining atomic.Bool
 }
 
 // New creates a new Volt application with the given options.
@@ -51,10 +75,13 @@ func New(opts ...Option) *App {
 
 	// Create app
 	app := &App{
-		config:   cfg,
-		router:   r,
-		registry: NewRegistry(),
-		logger:   cfg.Logger,
+		config:     cfg,
+		router:     r,
+		registry:   NewRegistry(),
+		logger:     cfg.Logger,
+		quietPaths: map[string]bool{},
+
+		errorTranslators: append(append([]ErrorTranslator{}, cfg.ErrorTranslators...), defaultErrorTranslators...),
 	}
 
 	// Setup OTEL if configured
@@ -71,6 +98,16 @@ func New(opts ...Option) *App {
 	// Setup default middleware stack
 	app.setupMiddleware()
 
+	// Prometheus scrape endpoint, served outside the OpenAPI spec
+	if handler := app.otel.MetricsHandler(); handler != nil {
+		path := cfg.OTEL.PrometheusPath
+		if path == "" {
+			path = "/metrics"
+		}
+		app.quietPaths[path] = true
+		r.Method(http.MethodGet, path, handler)
+	}
+
 	// Create Huma API for OpenAPI generation
 	humaConfig := huma.DefaultConfig(cfg.Name, cfg.Version)
 	humaConfig.Info.Description = cfg.Description
@@ -86,8 +123,22 @@ func New(opts ...Option) *App {
 		}
 	}
 
+	installErrorModel()
 	app.api = humachi.New(r, humaConfig)
 
+	// Per-operation metrics wrap everything else so rejections are counted
+	app.api.UseMiddleware(app.operationMetricsMiddleware())
+	app.api.UseMiddleware(app.requestPathMiddleware)
+	app.api.UseMiddleware(app.recoverMiddleware)
+	if cfg.Messages != nil {
+		app.api.UseMiddleware(app.localeMiddleware)
+	}
+
+	// Enforce declared rate limits and authorization requirements on every
+	// operation. Rate limits run first so rejected clients never reach the policy.
+	app.api.UseMiddleware(app.rateLimitMiddleware())
+	app.api.UseMiddleware(app.authzMiddleware())
+
 	return app
 }
 
@@ -96,18 +147,21 @@ func (a *App) setupMiddleware() {
 	// Request ID for tracing correlation
 	a.router.Use(middleware.RequestID)
 
+	// Inbound headers for propagation to outbound service calls
+	a.router.Use(inboundHeadersMiddleware)
+
 	// Real IP detection
 	a.router.Use(middleware.RealIP)
 
-	// Recovery from panics
-	a.router.Use(middleware.Recoverer)
-
 	// OTEL HTTP instrumentation
 	if a.otel != nil {
 		a.router.Use(func(next http.Handler) http.Handler {
 			return otelhttp.NewHandler(next, "http.request",
 				otelhttp.WithTracerProvider(a.otel.TracerProvider()),
 				otelhttp.WithMeterProvider(a.otel.MeterProvider()),
+				otelhttp.WithFilter(func(r *http.Request) bool {
+					return !a.quietPaths[r.URL.Path]
+				}),
 			)
 		})
 	}
@@ -115,6 +169,10 @@ func (a *App) setupMiddleware() {
 	// Structured logging
 	a.router.Use(a.loggingMiddleware())
 
+	// Recovery from panics, inside logging and tracing so both see the 500
+	a.panics = a.newPanicCounter()
+	a.router.Use(a.recoverer)
+
 	// Timeout
 	if a.config.Server.RequestTimeout > 0 {
 		a.router.Use(middleware.Timeout(a.config.Server.RequestTimeout))
@@ -125,6 +183,11 @@ func (a *App) setupMiddleware() {
 func (a *App) loggingMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if a.quietPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			start := time.Now()
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
@@ -179,11 +242,11 @@ func (a *App) OnStop(fn func(context.Context) error) {
 	a.onStop = append(a.onStop, fn)
 }
 
-// Run starts the application and blocks until shutdown.
-func (a *App) Run() error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
+// Start runs start hooks and initializes registered services, then marks
+// the application as started. Run calls it once the server is listening, so
+// probes can report progress; call it directly when serving the router
+// yourself (e.g. in tests).
+func (a *App) Start(ctx context.Context) error {
 	// Run start hooks
 	for _, fn := range a.onStart {
 		if err := fn(ctx); err != nil {
@@ -196,35 +259,71 @@ func (a *App) Run() error {
 		return fmt.Errorf("service initialization failed: %w", err)
 	}
 
-	// Create HTTP server
+	a.started.Store(true)
+	return nil
+}
+
+// Started reports whether Start has completed.
+func (a *App) Started() bool {
+	return a.started.Load()
+}
+
+// Ready reports whether the application should receive traffic: it has
+// started and Shutdown has not begun.
+func (a *App) Ready() bool {
+	return a.started.Load() && !a.draining.Load()
+}
+
+// Run starts the application and blocks until shutdown.
+func (a *App) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Listen before start hooks run so the probes can answer; readiness
+	// stays false and other routes respond with 503 until Start completes.
+	// Binding first also fails fast, before any service is initialized.
 	addr := fmt.Sprintf("%s:%d", a.config.Server.Host, a.config.Server.Port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		a.releaseAfterFailure(ctx, false)
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	// Create HTTP server
 	a.server = &http.Server{
 		Addr:         addr,
-		Handler:      a.router,
+		Handler:      a.startGate(a.router),
 		ReadTimeout:  a.config.Server.ReadTimeout,
 		WriteTimeout: a.config.Server.WriteTimeout,
 		IdleTimeout:  a.config.Server.IdleTimeout,
 	}
 
-	// Start server in goroutine
+	// Serve in goroutine
 	errChan := make(chan error, 1)
 	go func() {
 		a.logger.Info("starting server",
-			"addr", addr,
+			"addr", ln.Addr().String(),
 			"name", a.config.Name,
 			"version", a.config.Version,
 		)
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := a.server.Serve(ln); err != nil && err != http.ErrServerClosed {
 			errChan <- err
 		}
 	}()
 
+	if err := a.Start(ctx); err != nil {
+		a.server.Close()
+		a.releaseAfterFailure(ctx, false)
+		return err
+	}
+
 	// Wait for shutdown signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	select {
 	case err := <-errChan:
+		a.releaseAfterFailure(ctx, true)
 		return err
 	case sig := <-sigChan:
 		a.logger.Info("received shutdown signal", "signal", sig)
@@ -233,6 +332,17 @@ func (a *App) Run() error {
 	return a.Shutdown(ctx)
 }
 
+// releaseAfterFailure releases what Run set up when it fails, running stop
+// hooks only if the app had started.
+func (a *App) releaseAfterFailure(ctx context.Context, started bool) {
+	releaseCtx, cancel := context.WithTimeout(ctx, a.config.Server.ShutdownTimeout)
+	defer cancel()
+	if started {
+		a.runStopHooks(releaseCtx)
+	}
+	a.releaseServices(releaseCtx)
+}
+
 // Shutdown gracefully shuts down the application.
 func (a *App) Shutdown(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, a.config.Server.ShutdownTimeout)
@@ -240,6 +350,16 @@ func (a *App) Shutdown(ctx context.Context) error {
 
 	a.logger.Info("shutting down server")
 
+	// Fail readiness first and give load balancers time to notice before
+	// the server stops accepting connections
+	a.draining.Store(true)
+	if a.server != nil && a.config.Server.DrainDelay > 0 {
+		select {
+		case <-time.After(a.config.Server.DrainDelay):
+		case <-shutdownCtx.Done():
+		}
+	}
+
 	// Shutdown HTTP server
 	if a.server != nil {
 		if err := a.server.Shutdown(shutdownCtx); err != nil {
@@ -247,25 +367,39 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
-	// Run stop hooks in reverse order
+	a.runStopHooks(shutdownCtx)
+	a.releaseServices(shutdownCtx)
+
+	a.logger.Info("shutdown complete")
+	return nil
+}
+
+// runStopHooks runs stop hooks in reverse order.
+func (a *App) runStopHooks(ctx context.Context) {
 	for i := len(a.onStop) - 1; i >= 0; i-- {
-		if err := a.onStop[i](shutdownCtx); err != nil {
+		if err := a.onStop[i](ctx); err != nil {
 			a.logger.Error("stop hook failed", "error", err)
 		}
 	}
+}
 
+// releaseServices shuts down registered services, the rate limit store and
+// OTEL.
+func (a *App) releaseServices(ctx context.Context) {
 	// Shutdown services
-	if err := a.registry.Shutdown(shutdownCtx); err != nil {
+	if err := a.registry.Shutdown(ctx); err != nil {
 		a.logger.Error("service shutdown error", "error", err)
 	}
 
+	// Stop rate limit sweeper
+	if store := a.rateLimitStore.Load(); store != nil {
+		_ = store.Close()
+	}
+
 	// Shutdown OTEL
 	if a.otel != nil {
-		if err := a.otel.Shutdown(shutdownCtx); err != nil {
+		if err := a.otel.Shutdown(ctx); err != nil {
 			a.logger.Error("OTEL shutdown error", "error", err)
 		}
 	}
-
-	a.logger.Info("shutdown complete")
-	return nil
 }
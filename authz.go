@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 )
 
 // =============================================================================
@@ -116,19 +117,17 @@ func (c *CompositePolicy) Authorize(ctx context.Context, req AuthzRequest, requi
 // =============================================================================
 
 // SetAuthzPolicy registers the authorization policy for the application.
+// The policy is consulted on every request to an operation that declares an
+// AuthzRequirement, so it may be set before or after operations are registered.
 func SetAuthzPolicy(app *App, policy AuthzPolicy) {
 	app.authzPolicy = policy
 }
 
 // authzMiddleware creates the Huma middleware that enforces authorization.
+// It is installed on the API by New, so every operation registered through
+// Register is covered.
 func (a *App) authzMiddleware() func(ctx huma.Context, next func(huma.Context)) {
 	return func(ctx huma.Context, next func(huma.Context)) {
-		// Skip if no policy configured
-		if a.authzPolicy == nil {
-			next(ctx)
-			return
-		}
-
 		// Get requirement from operation metadata
 		requirement, ok := ctx.Operation().Metadata["authz"].(AuthzRequirement)
 		if !ok {
@@ -137,9 +136,24 @@ func (a *App) authzMiddleware() func(ctx huma.Context, next func(huma.Context))
 			return
 		}
 
+		// Requirement declared but nothing to enforce it - fail closed unless
+		// explicitly configured otherwise
+		if a.authzPolicy == nil {
+			if a.config.Authz.FailOpen {
+				next(ctx)
+				return
+			}
+			a.logger.Error("authorization policy not configured",
+				"operation", ctx.Operation().OperationID,
+				"path", ctx.Operation().Path,
+			)
+			a.writeError(ctx, ErrInternal("authorization policy not configured"))
+			return
+		}
+
 		// Build authorization request
 		req := AuthzRequest{
-			HTTP:        nil, // Will be set by adapter
+			HTTP:        requestFromContext(ctx),
 			PathParams:  extractPathParams(ctx),
 			QueryParams: extractQueryParams(ctx),
 			Operation:   ctx.Operation(),
@@ -147,8 +161,7 @@ func (a *App) authzMiddleware() func(ctx huma.Context, next func(huma.Context))
 
 		// Check authorization
 		if err := a.authzPolicy.Authorize(ctx.Context(), req, requirement); err != nil {
-			status := StatusFromError(err)
-			_ = huma.WriteErr(a.api, ctx, status, err.Error())
+			a.writeError(ctx, err)
 			return
 		}
 
@@ -156,6 +169,12 @@ func (a *App) authzMiddleware() func(ctx huma.Context, next func(huma.Context))
 	}
 }
 
+// requestFromContext returns the *http.Request backing a Huma context.
+func requestFromContext(ctx huma.Context) *http.Request {
+	r, _ := humachi.Unwrap(ctx)
+	return r
+}
+
 // extractPathParams extracts path parameters from the Huma context.
 func extractPathParams(ctx huma.Context) map[string]string {
 	params := make(map[string]string)
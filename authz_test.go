@@ -2,8 +2,12 @@ package volt
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/google/uuid"
@@ -186,6 +190,108 @@ func TestAuthzPolicyFunc(t *testing.T) {
 	})
 }
 
+func TestAuthzMiddleware(t *testing.T) {
+	type input struct {
+		ID string `path:"id"`
+	}
+
+	register := func(app *App) {
+		Register(app, WithAuthz(Operation{
+			Method:      "GET",
+			Path:        "/items/{id}",
+			OperationID: "get-item",
+		}, Authz("item", "id", "viewer")), func(ctx context.Context, in *input) (*StatusOutput, error) {
+			out := &StatusOutput{}
+			out.Body.Status = "ok"
+			return out, nil
+		})
+
+		Register(app, Operation{
+			Method:      "GET",
+			Path:        "/public",
+			OperationID: "public",
+		}, func(ctx context.Context, in *EmptyInput) (*StatusOutput, error) {
+			out := &StatusOutput{}
+			out.Body.Status = "ok"
+			return out, nil
+		})
+	}
+
+	t.Run("enforces policy on registered operations", func(t *testing.T) {
+		app := newTestApp()
+		register(app)
+
+		var got AuthzRequest
+		var gotRequirement AuthzRequirement
+		SetAuthzPolicy(app, AuthzPolicyFunc(func(ctx context.Context, req AuthzRequest, requirement AuthzRequirement) error {
+			got = req
+			gotRequirement = requirement
+			return ErrForbidden("denied")
+		}))
+
+		req := httptest.NewRequest("GET", "/items/abc?x=1", nil)
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, req)
+
+		assertEqual(t, http.StatusForbidden, rec.Code)
+		var problem ErrorModel
+		assertNil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assertEqual(t, "FORBIDDEN", problem.Code)
+		assertEqual(t, "denied", problem.Detail)
+		assertEqual(t, "/items/abc", problem.Instance)
+		assertEqual(t, "item", gotRequirement.Resource)
+		assertEqual(t, "abc", got.PathParams["id"])
+		assertNotNil(t, got.HTTP)
+		assertEqual(t, "/items/abc", got.HTTP.URL.Path)
+		assertEqual(t, "get-item", got.Operation.OperationID)
+	})
+
+	t.Run("allows when policy passes", func(t *testing.T) {
+		app := newTestApp()
+		register(app)
+		SetAuthzPolicy(app, NoopPolicy)
+
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/items/abc", nil))
+
+		assertEqual(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("does not consult policy for operations without requirement", func(t *testing.T) {
+		app := newTestApp()
+		register(app)
+		SetAuthzPolicy(app, AuthzPolicyFunc(func(ctx context.Context, req AuthzRequest, requirement AuthzRequirement) error {
+			t.Error("policy should not be called")
+			return nil
+		}))
+
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/public", nil))
+
+		assertEqual(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("fails closed when no policy is set", func(t *testing.T) {
+		app := newTestApp()
+		register(app)
+
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/items/abc", nil))
+
+		assertEqual(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("fails open when configured", func(t *testing.T) {
+		app := newTestApp(WithAuthzFailOpen())
+		register(app)
+
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/items/abc", nil))
+
+		assertEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
 // =============================================================================
 // Example: Resource-Based RBAC Policy Tests
 // =============================================================================
@@ -421,6 +527,12 @@ func TestResourceRBACPolicy(t *testing.T) {
 // Test Helpers
 // =============================================================================
 
+// newTestApp creates an App with a discarding logger for in-process tests.
+func newTestApp(opts ...Option) *App {
+	opts = append([]Option{WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))}, opts...)
+	return New(opts...)
+}
+
 func assertEqual[T comparable](t *testing.T, expected, actual T) {
 	t.Helper()
 	if expected != actual {
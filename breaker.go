@@ -0,0 +1,296 @@
+package volt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// --- Circuit Breaker ---
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through while tracking failures.
+	CircuitClosed CircuitState = iota
+	// CircuitHalfOpen lets a limited number of trial requests through.
+	CircuitHalfOpen
+	// CircuitOpen rejects requests without calling the service.
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures the circuit breaker of an HTTP service.
+type CircuitBreakerConfig struct {
+	// Rolling window over which the failure ratio is computed (default: 30s)
+	Window time.Duration
+
+	// Minimum requests in the window before the breaker may open (default: 20)
+	MinRequests int
+
+	// Failure ratio in the window that opens the breaker (default: 0.5)
+	FailureRatio float64
+
+	// How long the breaker stays open before trial requests (default: 30s)
+	OpenTimeout time.Duration
+
+	// Trial requests allowed while half-open; all must succeed for the
+	// breaker to close, any failure reopens it (default: 1)
+	HalfOpenRequests int
+
+	// Decides whether a call failed (default: transport errors other than
+	// context cancellation, and 5xx responses)
+	IsFailure func(resp *http.Response, err error) bool
+
+	// Time source (default: time.Now). Override in tests.
+	Clock func() time.Time
+}
+
+// CircuitOpenError is returned by a service's http.Client while its circuit
+// breaker is open. It converts to ErrServiceUnavailable with errors.As, so
+// handlers can return it directly and clients get a 503.
+type CircuitOpenError struct {
+	Service string
+
+	// Time until the breaker allows a trial request
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for %q is open", e.Service)
+}
+
+// As converts the error to *Error and huma.StatusError.
+func (e *CircuitOpenError) As(target any) bool {
+	switch t := target.(type) {
+	case **Error:
+		*t = CodeCircuitOpen.New(e.Error())
+		return true
+	case *huma.StatusError:
+		*t = huma.NewError(http.StatusServiceUnavailable, e.Error())
+		return true
+	}
+	return false
+}
+
+// WithHTTPCircuitBreaker enables a circuit breaker for the service.
+// Zero fields in config use their defaults.
+func WithHTTPCircuitBreaker(config CircuitBreakerConfig) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.CircuitBreaker = &config
+	}
+}
+
+// circuitBucketCount is the resolution of the rolling window.
+const circuitBucketCount = 10
+
+type circuitBucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+type circuitBreaker struct {
+	name   string
+	config CircuitBreakerConfig
+
+	mu         sync.Mutex
+	state      CircuitState
+	generation uint64 // incremented on every transition
+	openedAt   time.Time
+	buckets    [circuitBucketCount]circuitBucket
+	trials     int // half-open requests started
+	successes  int // half-open requests succeeded
+}
+
+func newCircuitBreaker(name string, config CircuitBreakerConfig) *circuitBreaker {
+	if config.Window <= 0 {
+		config.Window = 30 * time.Second
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 20
+	}
+	if config.FailureRatio <= 0 {
+		config.FailureRatio = 0.5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = defaultIsFailure
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &circuitBreaker{name: name, config: config}
+}
+
+func defaultIsFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= 500
+}
+
+// State returns the current state, moving from open to half-open once the
+// open timeout has passed.
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(b.config.Clock())
+	return b.state
+}
+
+// allow reports whether a request may proceed. If so, done must be called
+// with the outcome.
+func (b *circuitBreaker) allow() (done func(failed bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.config.Clock()
+	b.refresh(now)
+
+	switch b.state {
+	case CircuitOpen:
+		return nil, &CircuitOpenError{Service: b.name, RetryAfter: b.openedAt.Add(b.config.OpenTimeout).Sub(now)}
+	case CircuitHalfOpen:
+		if b.trials >= b.config.HalfOpenRequests {
+			return nil, &CircuitOpenError{Service: b.name}
+		}
+		b.trials++
+	}
+
+	generation := b.generation
+	return func(failed bool) { b.record(generation, failed) }, nil
+}
+
+// record applies an outcome, ignoring requests started in an earlier state.
+func (b *circuitBreaker) record(generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	now := b.config.Clock()
+
+	switch b.state {
+	case CircuitClosed:
+		bucket := b.bucket(now)
+		bucket.requests++
+		if failed {
+			bucket.failures++
+		}
+
+		requests, failures := b.totals(now)
+		if requests >= b.config.MinRequests && float64(failures)/float64(requests) >= b.config.FailureRatio {
+			b.transition(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			b.transition(CircuitOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.transition(CircuitClosed, now)
+		}
+	}
+}
+
+func (b *circuitBreaker) refresh(now time.Time) {
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(b.config.OpenTimeout)) {
+		b.transition(CircuitHalfOpen, now)
+	}
+}
+
+func (b *circuitBreaker) transition(state CircuitState, now time.Time) {
+	b.state = state
+	b.generation++
+	b.trials, b.successes = 0, 0
+	b.buckets = [circuitBucketCount]circuitBucket{}
+	if state == CircuitOpen {
+		b.openedAt = now
+	}
+}
+
+// bucket returns the bucket for now, resetting it if it holds stale counts.
+func (b *circuitBreaker) bucket(now time.Time) *circuitBucket {
+	// Windows under circuitBucketCount nanoseconds get 1ns buckets
+	width := max(b.config.Window/circuitBucketCount, 1)
+	start := now.Truncate(width)
+	bucket := &b.buckets[(start.UnixNano()/int64(width))%circuitBucketCount]
+	if !bucket.start.Equal(start) {
+		*bucket = circuitBucket{start: start}
+	}
+	return bucket
+}
+
+// totals sums the buckets inside the rolling window.
+func (b *circuitBreaker) totals(now time.Time) (requests, failures int) {
+	cutoff := now.Add(-b.config.Window)
+	for _, bucket := range b.buckets {
+		if bucket.start.After(cutoff) {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
+
+// registerMetrics reports the breaker state as a gauge
+// (0 = closed, 1 = half-open, 2 = open).
+func (b *circuitBreaker) registerMetrics(meter metric.Meter) (metric.Registration, error) {
+	state, err := meter.Int64ObservableGauge("http.client.circuit_breaker.state",
+		metric.WithDescription("Circuit breaker state: 0 closed, 1 half-open, 2 open"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := metric.WithAttributes(attribute.String("service", b.name))
+	return meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		o.ObserveInt64(state, int64(b.State()), attrs)
+		return nil
+	}, state)
+}
+
+// circuitBreakerRoundTripper rejects requests while the breaker is open.
+type circuitBreakerRoundTripper struct {
+	base    http.RoundTripper
+	breaker *circuitBreaker
+}
+
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := rt.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.base.RoundTrip(req)
+	done(rt.breaker.config.IsFailure(resp, err))
+	return resp, err
+}
@@ -0,0 +1,174 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	newBreaker := func(clock *fakeClock) *circuitBreaker {
+		return newCircuitBreaker("billing", CircuitBreakerConfig{
+			Window:       10 * time.Second,
+			MinRequests:  4,
+			FailureRatio: 0.5,
+			OpenTimeout:  5 * time.Second,
+			Clock:        clock.Now,
+		})
+	}
+
+	call := func(b *circuitBreaker, failed bool) error {
+		done, err := b.allow()
+		if err != nil {
+			return err
+		}
+		done(failed)
+		return nil
+	}
+
+	t.Run("opens when failure ratio is reached", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newBreaker(clock)
+
+		// Below MinRequests the breaker stays closed
+		for i := 0; i < 3; i++ {
+			assertNil(t, call(b, true))
+		}
+		assertEqual(t, CircuitClosed, b.State())
+
+		assertNil(t, call(b, true))
+		assertEqual(t, CircuitOpen, b.State())
+
+		err := call(b, false)
+		var open *CircuitOpenError
+		assertTrue(t, errors.As(err, &open))
+		assertEqual(t, "billing", open.Service)
+		assertEqual(t, 5*time.Second, open.RetryAfter)
+	})
+
+	t.Run("stays closed below the failure ratio", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newBreaker(clock)
+
+		for i := 0; i < 10; i++ {
+			assertNil(t, call(b, i%4 == 0))
+		}
+		assertEqual(t, CircuitClosed, b.State())
+	})
+
+	t.Run("forgets failures outside the window", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newBreaker(clock)
+
+		for i := 0; i < 3; i++ {
+			call(b, true)
+		}
+		clock.Advance(11 * time.Second)
+		call(b, true)
+
+		assertEqual(t, CircuitClosed, b.State())
+	})
+
+	t.Run("half-open trial closes or reopens", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newBreaker(clock)
+		for i := 0; i < 4; i++ {
+			call(b, true)
+		}
+
+		clock.Advance(5 * time.Second)
+		assertEqual(t, CircuitHalfOpen, b.State())
+
+		// Only one trial request is let through
+		done, err := b.allow()
+		assertNil(t, err)
+		_, err = b.allow()
+		assertNotNil(t, err)
+
+		done(true)
+		assertEqual(t, CircuitOpen, b.State())
+
+		clock.Advance(5 * time.Second)
+		assertNil(t, call(b, false))
+		assertEqual(t, CircuitClosed, b.State())
+	})
+
+	t.Run("windows shorter than its buckets", func(t *testing.T) {
+		b := newCircuitBreaker("billing", CircuitBreakerConfig{Window: 5, MinRequests: 1, Clock: newFakeClock().Now})
+		assertNil(t, call(b, false))
+		assertEqual(t, CircuitClosed, b.State())
+	})
+
+	t.Run("converts to a catalogued 503", func(t *testing.T) {
+		var err error = &CircuitOpenError{Service: "billing"}
+
+		var voltErr *Error
+		assertTrue(t, errors.As(err, &voltErr))
+		assertEqual(t, http.StatusServiceUnavailable, voltErr.GetStatus())
+		assertTrue(t, CodeCircuitOpen.Is(voltErr))
+		_, ok := LookupErrorCode(voltErr.Code())
+		assertTrue(t, ok)
+		assertEqual(t, http.StatusServiceUnavailable, StatusFromError(err))
+	})
+}
+
+func TestHTTPServiceCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	reader := sdkmetric.NewManualReader()
+	app := newTestApp(WithHealthCacheTTL(-1))
+	app.otel = &OTELProvider{meterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))}
+
+	RegisterHTTPService(app, "billing", func(c *http.Client) *http.Client { return c },
+		WithHTTPBaseURL(upstream.URL),
+		WithHTTPRetries(3, 0, 0),
+		WithHTTPCircuitBreaker(CircuitBreakerConfig{MinRequests: 2, OpenTimeout: time.Minute}),
+	)
+	RegisterHealthCheck(app, "")
+	ctx := context.Background()
+	assertNil(t, app.registry.Initialize(ctx, app))
+	defer app.registry.Shutdown(ctx)
+
+	client := app.registry.MustGet("billing").(*http.Client)
+
+	// The second failed attempt opens the breaker, which stops further retries
+	_, err := client.Get(upstream.URL)
+	assertEqual(t, int32(2), calls.Load())
+	assertEqual(t, http.StatusServiceUnavailable, StatusFromError(err))
+
+	rec := httptest.NewRecorder()
+	app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	assertNil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assertEqual(t, "degraded", body.Status)
+	assertEqual(t, "circuit breaker open", body.Checks["billing"])
+
+	var rm metricdata.ResourceMetrics
+	assertNil(t, reader.Collect(ctx, &rm))
+	var gauge metricdata.Gauge[int64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "http.client.circuit_breaker.state" {
+				gauge = m.Data.(metricdata.Gauge[int64])
+			}
+		}
+	}
+	assertEqual(t, 1, len(gauge.DataPoints))
+	assertEqual(t, int64(CircuitOpen), gauge.DataPoints[0].Value)
+}
@@ -0,0 +1,260 @@
+package volt
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// --- Error Catalog ---
+
+// ErrorCode declares a machine-readable error code clients can rely on.
+// Define codes once with DefineError and create errors from them:
+//
+//	var ErrUserNotFound = volt.DefineError(volt.ErrorCode{
+//	    Code:        "USER_NOT_FOUND",
+//	    Status:      404,
+//	    Title:       "User not found",
+//	    Description: "No user exists with the given ID.",
+//	})
+//
+//	return nil, ErrUserNotFound.Newf("user %s not found", id)
+type ErrorCode struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// Built-in codes used by Volt's constructors and error translators.
+var (
+	CodeBadRequest = DefineError(ErrorCode{Code: "BAD_REQUEST", Status: http.StatusBadRequest,
+		Description: "The request is malformed."})
+	CodeUnauthorized = DefineError(ErrorCode{Code: "UNAUTHORIZED", Status: http.StatusUnauthorized,
+		Description: "Authentication is required or the credentials are invalid."})
+	CodeForbidden = DefineError(ErrorCode{Code: "FORBIDDEN", Status: http.StatusForbidden,
+		Description: "The caller is not allowed to perform this operation."})
+	CodeNotFound = DefineError(ErrorCode{Code: "NOT_FOUND", Status: http.StatusNotFound,
+		Description: "The requested resource does not exist."})
+	CodeConflict = DefineError(ErrorCode{Code: "CONFLICT", Status: http.StatusConflict,
+		Description: "The request conflicts with the current state of the resource."})
+	CodeValidation = DefineError(ErrorCode{Code: "VALIDATION_ERROR", Status: http.StatusUnprocessableEntity,
+		Description: "The request is well-formed but contains invalid values."})
+	CodeRateLimited = DefineError(ErrorCode{Code: "RATE_LIMITED", Status: http.StatusTooManyRequests,
+		Description: "Too many requests; retry after the time in the Retry-After header."})
+	CodeClientClosedRequest = DefineError(ErrorCode{Code: "CLIENT_CLOSED_REQUEST", Status: StatusClientClosedRequest,
+		Description: "The client canceled the request before it completed."})
+	CodeInternal = DefineError(ErrorCode{Code: "INTERNAL_ERROR", Status: http.StatusInternalServerError,
+		Description: "An unexpected error occurred; report the trace ID if it persists."})
+	CodeServiceUnavailable = DefineError(ErrorCode{Code: "SERVICE_UNAVAILABLE", Status: http.StatusServiceUnavailable,
+		Description: "The service or one of its dependencies is temporarily unavailable."})
+	CodeCircuitOpen = DefineError(ErrorCode{Code: "CIRCUIT_OPEN", Status: http.StatusServiceUnavailable,
+		Description: "Calls to a failing dependency are paused by its circuit breaker; retry later."})
+	CodeTimeout = DefineError(ErrorCode{Code: "TIMEOUT", Status: http.StatusGatewayTimeout,
+		Description: "The request did not complete in time."})
+)
+
+var errorCatalog = struct {
+	sync.RWMutex
+	codes map[string]ErrorCode
+}{codes: map[string]ErrorCode{}}
+
+// DefineError adds code to the catalog and returns it. The title defaults
+// to the status text. Defining a code twice with different values panics,
+// as codes must mean the same thing everywhere.
+func DefineError(code ErrorCode) ErrorCode {
+	if code.Code == "" || code.Status < 400 || code.Status > 599 {
+		panic(fmt.Sprintf("volt: invalid error code definition %+v", code))
+	}
+	if code.Title == "" {
+		code.Title = statusText(code.Status)
+	}
+
+	errorCatalog.Lock()
+	defer errorCatalog.Unlock()
+	if existing, ok := errorCatalog.codes[code.Code]; ok && existing != code {
+		panic("volt: error code " + code.Code + " is already defined")
+	}
+	errorCatalog.codes[code.Code] = code
+	return code
+}
+
+// LookupErrorCode returns the definition of a code.
+func LookupErrorCode(code string) (ErrorCode, bool) {
+	errorCatalog.RLock()
+	defer errorCatalog.RUnlock()
+	c, ok := errorCatalog.codes[code]
+	return c, ok
+}
+
+// ErrorCatalog returns all defined codes, sorted by status and code.
+func ErrorCatalog() []ErrorCode {
+	errorCatalog.RLock()
+	codes := make([]ErrorCode, 0, len(errorCatalog.codes))
+	for _, c := range errorCatalog.codes {
+		codes = append(codes, c)
+	}
+	errorCatalog.RUnlock()
+
+	slices.SortFunc(codes, func(a, b ErrorCode) int {
+		return cmp.Or(cmp.Compare(a.Status, b.Status), strings.Compare(a.Code, b.Code))
+	})
+	return codes
+}
+
+// New creates an error with this code. The message defaults to the title.
+func (c ErrorCode) New(message string) *Error {
+	e := c.newError(message)
+	if c.Status >= 500 {
+		e.stack = callers()
+	}
+	return e
+}
+
+// Newf creates an error with this code and a formatted message.
+func (c ErrorCode) Newf(format string, args ...any) *Error {
+	e := c.newError(fmt.Sprintf(format, args...))
+	if c.Status >= 500 {
+		e.stack = callers()
+	}
+	return e
+}
+
+func (c ErrorCode) newError(message string) *Error {
+	if message == "" {
+		message = c.Title
+	}
+	return &Error{status: c.Status, code: c.Code, message: message}
+}
+
+// Is reports whether err, or any error it wraps, is an *Error with this
+// code.
+func (c ErrorCode) Is(err error) bool {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.code == c.Code {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// WithErrors declares the error codes an operation may return, so they are
+// listed in its OpenAPI responses with an example each.
+//
+// Example:
+//
+//	volt.Register(app, volt.WithErrors(volt.Operation{
+//	    Method: "GET",
+//	    Path:   "/users/{id}",
+//	}, ErrUserNotFound, volt.CodeForbidden), handleGetUser)
+func WithErrors(op Operation, codes ...ErrorCode) Operation {
+	if op.Metadata == nil {
+		op.Metadata = make(map[string]any)
+	}
+	declared, _ := op.Metadata["errors"].([]ErrorCode)
+	op.Metadata["errors"] = append(slices.Clone(declared), codes...)
+	return op
+}
+
+// documentErrorCodes adds a problem details response per status with an
+// example for each declared code.
+func documentErrorCodes(op *huma.Operation, registry huma.Registry, codes []ErrorCode) {
+	schema := registry.Schema(reflect.TypeOf(ErrorModel{}), true, "ErrorModel")
+	if op.Responses == nil {
+		op.Responses = make(map[string]*huma.Response)
+	}
+
+	for _, code := range codes {
+		status := strconv.Itoa(code.Status)
+		resp, ok := op.Responses[status]
+		if !ok {
+			resp = &huma.Response{
+				Description: statusText(code.Status),
+				Content: map[string]*huma.MediaType{
+					"application/problem+json": {Schema: schema, Examples: map[string]*huma.Example{}},
+				},
+			}
+			op.Responses[status] = resp
+		}
+
+		media := resp.Content["application/problem+json"]
+		if media == nil {
+			continue
+		}
+		if media.Examples == nil {
+			media.Examples = map[string]*huma.Example{}
+		}
+		media.Examples[code.Code] = &huma.Example{
+			Summary:     code.Title,
+			Description: code.Description,
+			Value: ErrorModel{
+				Title:  code.Title,
+				Status: code.Status,
+				Detail: code.Title,
+				Code:   code.Code,
+			},
+		}
+	}
+}
+
+// --- Catalog Page ---
+
+// RegisterErrorCatalog serves the error catalog at path (default:
+// /errors): an HTML page for browsers and JSON otherwise. Problem details
+// for defined codes then link to their entry via the type field, e.g.
+// "/errors#USER_NOT_FOUND". The page is not part of the OpenAPI spec.
+func RegisterErrorCatalog(app *App, path string) {
+	if path == "" {
+		path = "/errors"
+	}
+	app.errorCatalogPath = path
+
+	app.router.Get(path, func(w http.ResponseWriter, r *http.Request) {
+		codes := ErrorCatalog()
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = catalogPage.Execute(w, struct {
+				Name  string
+				Codes []ErrorCode
+			}{app.config.Name, codes})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(codes)
+	})
+}
+
+var catalogPage = template.Must(template.New("errors").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} error codes</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 60rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .5rem; border-bottom: 1px solid #ddd; vertical-align: top; }
+code { font-weight: 600; }
+:target { background: #fff8c5; }
+</style>
+</head>
+<body>
+<h1>{{.Name}} error codes</h1>
+<p>Error responses are <code>application/problem+json</code> documents whose <code>code</code> field is one of:</p>
+<table>
+<tr><th>Code</th><th>Status</th><th>Title</th><th>Description</th></tr>
+{{range .Codes}}<tr id="{{.Code}}"><td><code>{{.Code}}</code></td><td>{{.Status}}</td><td>{{.Title}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
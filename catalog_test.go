@@ -0,0 +1,182 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errTestUserNotFound = DefineError(ErrorCode{
+	Code:        "TEST_USER_NOT_FOUND",
+	Status:      http.StatusNotFound,
+	Title:       "User not found",
+	Description: "No user exists with the given ID.",
+})
+
+var errTestUserDeleted = DefineError(ErrorCode{
+	Code:   "TEST_USER_DELETED",
+	Status: http.StatusNotFound,
+})
+
+func TestDefineError(t *testing.T) {
+	t.Run("title defaults to status text", func(t *testing.T) {
+		assertEqual(t, "Not Found", errTestUserDeleted.Title)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		code, ok := LookupErrorCode("TEST_USER_NOT_FOUND")
+		assertTrue(t, ok)
+		assertEqual(t, errTestUserNotFound, code)
+		_, ok = LookupErrorCode("NOPE")
+		assertTrue(t, !ok)
+	})
+
+	t.Run("redefining with the same values is allowed", func(t *testing.T) {
+		assertEqual(t, errTestUserNotFound, DefineError(errTestUserNotFound))
+	})
+
+	t.Run("conflicting and invalid definitions panic", func(t *testing.T) {
+		panics := func(code ErrorCode) (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			DefineError(code)
+			return false
+		}
+		assertTrue(t, panics(ErrorCode{Code: "TEST_USER_NOT_FOUND", Status: http.StatusGone}))
+		assertTrue(t, panics(ErrorCode{Status: http.StatusBadRequest}))
+		assertTrue(t, panics(ErrorCode{Code: "TEST_OK", Status: http.StatusOK}))
+	})
+
+	t.Run("catalog is sorted and includes built-in codes", func(t *testing.T) {
+		codes := ErrorCatalog()
+		assertEqual(t, CodeBadRequest, codes[0])
+		var found bool
+		for i, code := range codes {
+			if i > 0 {
+				assertTrue(t, codes[i-1].Status <= code.Status)
+			}
+			found = found || code == errTestUserDeleted
+		}
+		assertTrue(t, found)
+	})
+}
+
+func TestErrorCodeConstructors(t *testing.T) {
+	err := errTestUserNotFound.Newf("user %s not found", "ada")
+	assertEqual(t, http.StatusNotFound, StatusFromError(err))
+	assertEqual(t, "TEST_USER_NOT_FOUND", err.Code())
+	assertEqual(t, "user ada not found", err.Error())
+	assertEqual(t, "", err.StackTrace())
+
+	assertEqual(t, "User not found", errTestUserNotFound.New("").Error())
+	assertTrue(t, CodeInternal.New("boom").StackTrace() != "")
+
+	wrapped := Wrap(err, "loading profile")
+	assertTrue(t, errTestUserNotFound.Is(wrapped))
+	assertTrue(t, !errTestUserDeleted.Is(wrapped))
+	assertTrue(t, !errTestUserNotFound.Is(errors.New("user not found")))
+	assertTrue(t, CodeNotFound.Is(ErrNotFound("user")))
+}
+
+func TestErrorCatalogDocumentation(t *testing.T) {
+	app, _ := newInMemoryApp(t)
+	RegisterErrorCatalog(app, "")
+
+	type userInput struct {
+		ID string `path:"id"`
+	}
+	Register(app, WithErrors(Operation{Method: "GET", Path: "/users/{id}"}, errTestUserNotFound, errTestUserDeleted, CodeForbidden),
+		func(ctx context.Context, in *userInput) (*struct{}, error) {
+			if in.ID == "gone" {
+				return nil, errTestUserDeleted.New("user was deleted")
+			}
+			return nil, errTestUserNotFound.Newf("user %s not found", in.ID)
+		})
+	Register(app, Operation{Method: "GET", Path: "/plain"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+		return nil, errors.New("boom")
+	})
+	Register(app, WithRateLimit(Operation{Method: "GET", Path: "/limited"}, OperationRateLimit{Requests: 1, Window: time.Minute}),
+		func(ctx context.Context, in *struct{}) (*struct{}, error) { return nil, nil })
+	RegisterSimple(app, "GET", "/raw", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	t.Run("declared codes are documented with examples", func(t *testing.T) {
+		responses := app.API().OpenAPI().Paths["/users/{id}"].Get.Responses
+		assertNotNil(t, responses["200"])
+		assertTrue(t, responses["default"] == nil)
+
+		notFound := responses["404"].Content["application/problem+json"]
+		assertEqual(t, "#/components/schemas/ErrorModel", notFound.Schema.Ref)
+		assertEqual(t, 2, len(notFound.Examples))
+		example := notFound.Examples["TEST_USER_NOT_FOUND"]
+		assertEqual(t, "User not found", example.Summary)
+		assertEqual(t, "No user exists with the given ID.", example.Description)
+		assertEqual(t, "TEST_USER_NOT_FOUND", example.Value.(ErrorModel).Code)
+
+		forbidden := responses["403"].Content["application/problem+json"]
+		assertNotNil(t, forbidden.Examples["FORBIDDEN"])
+	})
+
+	t.Run("undeclared operations keep the default response", func(t *testing.T) {
+		responses := app.API().OpenAPI().Paths["/plain"].Get.Responses
+		assertNotNil(t, responses["default"])
+	})
+
+	t.Run("problem details use the catalog title and link to it", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/users/ada", nil))
+		assertEqual(t, http.StatusNotFound, rec.Code)
+
+		var model ErrorModel
+		assertNil(t, json.Unmarshal(rec.Body.Bytes(), &model))
+		assertEqual(t, "User not found", model.Title)
+		assertEqual(t, "user ada not found", model.Detail)
+		assertEqual(t, "/errors#TEST_USER_NOT_FOUND", model.Type)
+	})
+
+	t.Run("problems written outside handlers link to it too", func(t *testing.T) {
+		typeOf := func(path string) string {
+			rec := httptest.NewRecorder()
+			app.Router().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			var model ErrorModel
+			assertNil(t, json.Unmarshal(rec.Body.Bytes(), &model))
+			return model.Type
+		}
+		assertEqual(t, "/errors#INTERNAL_ERROR", typeOf("/plain"))
+		serveApp(app, "GET", "/limited")
+		assertEqual(t, "/errors#RATE_LIMITED", typeOf("/limited"))
+		assertEqual(t, "/errors#INTERNAL_ERROR", typeOf("/raw"))
+	})
+
+	t.Run("catalog as JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/errors", nil))
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var codes []ErrorCode
+		assertNil(t, json.Unmarshal(rec.Body.Bytes(), &codes))
+		assertEqual(t, len(ErrorCatalog()), len(codes))
+	})
+
+	t.Run("catalog as HTML", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/errors", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+		app.Router().ServeHTTP(rec, req)
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertTrue(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html"))
+		assertTrue(t, strings.Contains(rec.Body.String(), `<tr id="TEST_USER_NOT_FOUND">`))
+		assertTrue(t, strings.Contains(rec.Body.String(), "No user exists with the given ID."))
+	})
+
+	t.Run("catalog is not in the OpenAPI spec", func(t *testing.T) {
+		_, ok := app.API().OpenAPI().Paths["/errors"]
+		assertTrue(t, !ok)
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bermos/volt"
+	"github.com/bermos/volt/cmd/volt/internal/widgets"
+)
+
+// These tests exercise the golden client generated from the test API.
+
+func TestGeneratedClient(t *testing.T) {
+	app := newWidgetsApp()
+	server := httptest.NewServer(app.Router())
+	defer server.Close()
+
+	client := widgets.NewWithBaseURL(server.URL)(server.Client())
+	ctx := context.Background()
+
+	t.Run("typed output", func(t *testing.T) {
+		w, err := client.GetWidget(ctx, &widgets.GetWidgetInput{ID: "w1"})
+		assertNil(t, err)
+		assertEqual(t, "Sprocket", w.Name)
+		assertTrue(t, w.CreatedAt.Equal(widgetCreatedAt))
+		assertTrue(t, w.Color == nil)
+	})
+
+	t.Run("query and header parameters", func(t *testing.T) {
+		list, err := client.ListWidgets(ctx, &widgets.ListWidgetsInput{
+			Tag:    []string{"a", "b"},
+			Limit:  widgets.Ptr[int64](2),
+			XTrace: widgets.Ptr("trace-1"),
+		})
+		assertNil(t, err)
+		assertEqual(t, 2, len(list))
+		assertEqual(t, "trace-1", list[0].Name)
+		assertEqual(t, "a,b", strings.Join(list[1].Tags, ","))
+	})
+
+	t.Run("request body", func(t *testing.T) {
+		color := widgets.CreateWidgetInputBodyColorBlue
+		w, err := client.CreateWidget(ctx, &widgets.CreateWidgetInput{
+			Body: widgets.CreateWidgetInputBody{Name: "Gear", Color: &color},
+		})
+		assertNil(t, err)
+		assertEqual(t, "Gear", w.Name)
+		assertEqual(t, widgets.WidgetColorBlue, *w.Color)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		assertNil(t, client.DeleteWidget(ctx, &widgets.DeleteWidgetInput{ID: "w1"}))
+	})
+
+	t.Run("errors decode into volt.Error", func(t *testing.T) {
+		_, err := client.GetWidget(ctx, &widgets.GetWidgetInput{ID: "missing"})
+		var verr *volt.Error
+		assertTrue(t, errors.As(err, &verr))
+		assertEqual(t, http.StatusNotFound, verr.GetStatus())
+		assertTrue(t, volt.IsNotFound(err))
+
+		_, err = client.CreateWidget(ctx, &widgets.CreateWidgetInput{})
+		assertTrue(t, errors.As(err, &verr))
+		assertEqual(t, http.StatusUnprocessableEntity, verr.GetStatus())
+		assertTrue(t, strings.Contains(verr.Detail(), "body.name"))
+	})
+}
+
+func TestGeneratedClientWithRegisteredService(t *testing.T) {
+	upstream := httptest.NewServer(newWidgetsApp().Router())
+	defer upstream.Close()
+
+	app := volt.New(volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	volt.RegisterHTTPService(app, "widgets", widgets.New, volt.WithHTTPBaseURL(upstream.URL+"/"))
+	assertNil(t, app.Registry().Initialize(context.Background(), app))
+	defer app.Registry().Shutdown(context.Background())
+
+	client, ok := app.Registry().MustGet("widgets").(*widgets.Client)
+	assertTrue(t, ok)
+
+	w, err := client.GetWidget(context.Background(), &widgets.GetWidgetInput{ID: "w1"})
+	assertNil(t, err)
+	assertEqual(t, "w1", w.ID)
+}
@@ -0,0 +1,978 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const defaultVoltImport = "github.com/bermos/volt"
+
+// GenerateOptions configures client generation.
+type GenerateOptions struct {
+	// Name of the generated package
+	Package string
+
+	// Import path of the volt package (default: github.com/bermos/volt)
+	VoltImport string
+
+	// Where the document came from, recorded in the file header
+	Source string
+}
+
+// GenerateClient turns an OpenAPI 3.1 (or 3.0) JSON document into the
+// source of a Go client package. Every operation becomes a method on Client
+// with typed input and output; non-2xx responses are returned as *volt.Error.
+func GenerateClient(spec []byte, opts GenerateOptions) ([]byte, error) {
+	var doc openAPIDoc
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document (JSON expected): %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q (3.x expected)", doc.OpenAPI)
+	}
+	if opts.Package == "" {
+		opts.Package = "client"
+	}
+	if opts.VoltImport == "" {
+		opts.VoltImport = defaultVoltImport
+	}
+
+	g := &generator{
+		doc:        &doc,
+		opts:       opts,
+		imports:    map[string]bool{},
+		sources:    map[string]*schema{},
+		types:      map[string]string{},
+		underlying: map[string]string{},
+	}
+	src, err := g.generate()
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, src)
+	}
+	return formatted, nil
+}
+
+// --- OpenAPI Document ---
+
+// Only the parts of the document needed for client generation are decoded.
+
+type openAPIDoc struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Paths      map[string]*pathItem `json:"paths"`
+	Components struct {
+		Schemas       map[string]*schema      `json:"schemas"`
+		Parameters    map[string]*parameter   `json:"parameters"`
+		RequestBodies map[string]*requestBody `json:"requestBodies"`
+		Responses     map[string]*response    `json:"responses"`
+	} `json:"components"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `json:"parameters"`
+	Get        *operation   `json:"get"`
+	Put        *operation   `json:"put"`
+	Post       *operation   `json:"post"`
+	Delete     *operation   `json:"delete"`
+	Options    *operation   `json:"options"`
+	Head       *operation   `json:"head"`
+	Patch      *operation   `json:"patch"`
+	Trace      *operation   `json:"trace"`
+}
+
+func (p *pathItem) operations() map[string]*operation {
+	return map[string]*operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch, "TRACE": p.Trace,
+	}
+}
+
+var methodOrder = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Deprecated  bool                 `json:"deprecated"`
+	Parameters  []*parameter         `json:"parameters"`
+	RequestBody *requestBody         `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Explode     *bool   `json:"explode"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Ref      string                `json:"$ref"`
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Ref         string                `json:"$ref"`
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaTypes        `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Enum                 []any              `json:"enum"`
+	Nullable             bool               `json:"nullable"` // OpenAPI 3.0
+	AllOf                []*schema          `json:"allOf"`
+	Deprecated           bool               `json:"deprecated"`
+}
+
+// schemaTypes accepts both "type": "string" and "type": ["string", "null"].
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// primary returns the first non-null type.
+func (s *schema) primary() string {
+	for _, t := range s.Type {
+		if t != "null" {
+			return t
+		}
+	}
+	return ""
+}
+
+func (s *schema) nullable() bool {
+	return s.Nullable || slices.Contains(s.Type, "null")
+}
+
+// additional returns the schema of additionalProperties, if it is one.
+func (s *schema) additional() *schema {
+	if len(s.AdditionalProperties) == 0 || s.AdditionalProperties[0] != '{' {
+		return nil
+	}
+	var ap schema
+	if err := json.Unmarshal(s.AdditionalProperties, &ap); err != nil {
+		return nil
+	}
+	return &ap
+}
+
+// --- Generator ---
+
+type generator struct {
+	doc  *openAPIDoc
+	opts GenerateOptions
+
+	imports    map[string]bool
+	sources    map[string]*schema // type name -> schema it was declared for
+	types      map[string]string  // type name -> declaration
+	underlying map[string]string  // type name -> underlying Go type ("struct" for structs)
+	methods    bytes.Buffer
+}
+
+func (g *generator) generate() ([]byte, error) {
+	for _, name := range sortedKeys(g.doc.Components.Schemas) {
+		g.declareNamed(goName(name), g.doc.Components.Schemas[name], fmt.Sprintf("is the %s schema.", name))
+	}
+
+	paths := sortedKeys(g.doc.Paths)
+	seen := map[string]string{}
+	for _, path := range paths {
+		item := g.doc.Paths[path]
+		ops := item.operations()
+		for _, method := range methodOrder {
+			op := ops[method]
+			if op == nil {
+				continue
+			}
+			name := goName(op.OperationID)
+			if op.OperationID == "" {
+				name = goName(strings.ToLower(method) + " " + path)
+			}
+			if prev, dup := seen[name]; dup {
+				return nil, fmt.Errorf("operations %s and %s %s both map to method %s", prev, method, path, name)
+			}
+			seen[name] = method + " " + path
+			if err := g.operation(name, method, path, item, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	source := g.opts.Source
+	if source == "" {
+		source = "an OpenAPI document"
+	}
+	fmt.Fprintf(&out, "// Code generated by volt generate client from %s. DO NOT EDIT.\n\n", source)
+
+	title := g.doc.Info.Title
+	if title == "" {
+		title = "the"
+	}
+	fmt.Fprintf(&out, "// Package %s is a client for %s API", g.opts.Package, title)
+	if g.doc.Info.Version != "" {
+		fmt.Fprintf(&out, " (version %s)", g.doc.Info.Version)
+	}
+	out.WriteString(".\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.opts.Package)
+
+	imports := []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "strings"}
+	for imp := range g.imports {
+		if !slices.Contains(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	sort.Strings(imports)
+	out.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&out, "\n\t%q\n)\n\n", g.opts.VoltImport)
+
+	out.WriteString(clientRuntime)
+	out.Write(g.methods.Bytes())
+
+	out.WriteString("// --- Types ---\n\n")
+	for _, name := range sortedKeys(g.types) {
+		out.WriteString(g.types[name])
+	}
+	return out.Bytes(), nil
+}
+
+// clientRuntime is emitted verbatim into every generated package.
+const clientRuntime = `// Client calls the API over an *http.Client.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a client that sends path-only request URLs, which the
+// *http.Client resolves against the service. Its signature matches the
+// volt.RegisterHTTPService factory:
+//
+//	volt.RegisterHTTPService(app, "name", New, volt.WithHTTPBaseURL("http://service:8080"))
+func New(httpClient *http.Client) *Client {
+	return &Client{httpClient: httpClient}
+}
+
+// NewWithBaseURL returns a factory for clients that send requests to
+// baseURL, for use with a plain *http.Client.
+func NewWithBaseURL(baseURL string) func(httpClient *http.Client) *Client {
+	return func(httpClient *http.Client) *Client {
+		return &Client{httpClient: httpClient, baseURL: strings.TrimSuffix(baseURL, "/")}
+	}
+}
+
+// Ptr returns a pointer to v, for optional fields and parameters.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        any // encoded as JSON unless []byte
+	contentType string
+}
+
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	var body io.Reader
+	switch b := r.body.(type) {
+	case nil:
+	case []byte:
+		body = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("encoding %s %s request: %w", r.method, r.path, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return volt.ErrorFromResponse(resp)
+	}
+
+	switch o := out.(type) {
+	case nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+	case *[]byte:
+		if *o, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf("reading %s %s response: %w", r.method, r.path, err)
+		}
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding %s %s response: %w", r.method, r.path, err)
+		}
+	}
+	return nil
+}
+
+`
+
+// declareNamed declares a type for a component or inline schema and returns
+// its name, which gets a numeric suffix if another schema already took it.
+func (g *generator) declareNamed(name string, s *schema, about string) string {
+	base := name
+	for i := 2; g.sources[name] != nil; i++ {
+		if g.sources[name] == s {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+	g.sources[name] = s // reserve, schemas may be recursive
+	s = flattenAllOf(s)
+
+	var b strings.Builder
+	b.WriteString("// " + name + " " + about + "\n")
+	if s.Description != "" || s.Deprecated {
+		b.WriteString("//\n")
+		writeDoc(&b, "", "", s.Description, s.Deprecated)
+	}
+
+	if s.Ref == "" && len(s.Properties) > 0 {
+		g.underlying[name] = "struct"
+		b.WriteString("type " + name + " struct {\n")
+		g.writeFields(&b, name, s)
+		b.WriteString("}\n\n")
+		g.types[name] = b.String()
+		return name
+	}
+
+	var typ string
+	if s.Ref == "" && s.primary() == "string" && len(s.Enum) > 0 {
+		typ = "string"
+	} else {
+		typ = g.goType(s, name+"Item")
+	}
+	if typ == "any" {
+		// Keep unknown values undecoded rather than behind a named interface
+		typ = "json.RawMessage"
+	}
+	g.underlying[name] = typ
+	if u, ok := g.underlying[typ]; ok {
+		g.underlying[name] = u
+	}
+	fmt.Fprintf(&b, "type %s %s\n\n", name, typ)
+
+	// String enums get a constant per value
+	if typ == "string" && len(s.Enum) > 0 {
+		b.WriteString("const (\n")
+		for _, v := range s.Enum {
+			if str, ok := v.(string); ok {
+				fmt.Fprintf(&b, "\t%s%s %s = %q\n", name, goName(str), name, str)
+			}
+		}
+		b.WriteString(")\n\n")
+	}
+	g.types[name] = b.String()
+	return name
+}
+
+func (g *generator) writeFields(b *strings.Builder, structName string, s *schema) {
+	used := map[string]bool{}
+	for _, prop := range sortedKeys(s.Properties) {
+		ps := s.Properties[prop]
+		field := goName(prop)
+		for i := 2; used[field]; i++ {
+			field = goName(prop) + strconv.Itoa(i)
+		}
+		used[field] = true
+
+		required := slices.Contains(s.Required, prop)
+		typ := g.goType(ps, structName+field)
+		if (!required || ps.nullable()) && !g.nilable(typ) {
+			typ = "*" + typ
+		}
+		tag := prop
+		if !required {
+			tag += ",omitempty"
+		}
+
+		writeDoc(b, "\t", "", ps.Description, ps.Deprecated)
+		fmt.Fprintf(b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+}
+
+// goType returns the Go type for s, declaring named types for inline objects.
+func (g *generator) goType(s *schema, hint string) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return g.refType(s.Ref)
+	}
+	s = flattenAllOf(s)
+	if s.Ref != "" {
+		return g.refType(s.Ref)
+	}
+
+	switch s.primary() {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "binary":
+			return "[]byte"
+		}
+		if len(s.Enum) > 0 {
+			return g.declareNamed(hint, s, "is an enumerated string; see the constants below.")
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items, hint)
+	case "object", "":
+		if len(s.Properties) > 0 {
+			return g.declareNamed(hint, s, "is an inline object schema.")
+		}
+		if ap := s.additional(); ap != nil {
+			return "map[string]" + g.goType(ap, hint+"Value")
+		}
+		if s.primary() == "object" {
+			return "map[string]any"
+		}
+	}
+	return "any"
+}
+
+func (g *generator) refType(ref string) string {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "any"
+	}
+	return goName(strings.TrimPrefix(ref, prefix))
+}
+
+// nilable reports whether typ already has a nil value, so optional fields
+// don't need a pointer.
+func (g *generator) nilable(typ string) bool {
+	if u, ok := g.underlying[typ]; ok {
+		typ = u
+	}
+	return strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "any" || typ == "json.RawMessage"
+}
+
+// flattenAllOf merges allOf members into a single schema, as generated by
+// specs composing a base type with extra fields.
+func flattenAllOf(s *schema) *schema {
+	if len(s.AllOf) == 0 {
+		return s
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return s.AllOf[0]
+	}
+	merged := *s
+	merged.AllOf = nil
+	merged.Properties = map[string]*schema{}
+	for k, v := range s.Properties {
+		merged.Properties[k] = v
+	}
+	for _, part := range s.AllOf {
+		part = flattenAllOf(part)
+		for k, v := range part.Properties {
+			merged.Properties[k] = v
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+	if len(merged.Type) == 0 {
+		merged.Type = schemaTypes{"object"}
+	}
+	return &merged
+}
+
+// --- Operations ---
+
+func (g *generator) operation(name, method, path string, item *pathItem, op *operation) error {
+	params, err := g.parameters(name, item, op)
+	if err != nil {
+		return err
+	}
+
+	body, bodyType, err := g.requestBody(op)
+	if err != nil {
+		return err
+	}
+
+	// Input struct
+	inputName := name + "Input"
+	hasInput := len(params) > 0 || body != nil
+	if hasInput {
+		var b strings.Builder
+		fmt.Fprintf(&b, "// %s holds the parameters of %s.\n", inputName, name)
+		fmt.Fprintf(&b, "type %s struct {\n", inputName)
+		for _, p := range params {
+			writeDoc(&b, "\t", "", p.Description, false)
+			fmt.Fprintf(&b, "\t%s %s // %s %s\n", p.field, p.typ, p.In, strconv.Quote(p.Name))
+		}
+		if body != nil {
+			typ := g.goType(body.schema, name+"Body")
+			if body.raw {
+				typ = "[]byte"
+			} else if !body.required && !g.nilable(typ) {
+				typ = "*" + typ
+			}
+			body.typ = typ
+			fmt.Fprintf(&b, "\tBody %s // %s\n", typ, bodyType)
+		}
+		b.WriteString("}\n\n")
+		g.methods.WriteString(b.String())
+	}
+
+	// Output
+	out, err := g.responseBody(name, op)
+	if err != nil {
+		return err
+	}
+
+	// Method
+	b := &g.methods
+	fmt.Fprintf(b, "// %s calls %s %s.\n", name, method, path)
+	if op.Summary != "" || op.Description != "" {
+		b.WriteString("//\n")
+		writeDoc(b, "", "", strings.TrimSpace(op.Summary+"\n\n"+op.Description), false)
+	}
+	if op.Deprecated {
+		b.WriteString("//\n// Deprecated: the operation is deprecated by the API.\n")
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context", name)
+	if hasInput {
+		fmt.Fprintf(b, ", in *%s", inputName)
+	}
+	b.WriteString(") ")
+	switch {
+	case out == nil:
+		b.WriteString("error {\n")
+	case out.pointer:
+		fmt.Fprintf(b, "(*%s, error) {\n", out.typ)
+	default:
+		fmt.Fprintf(b, "(%s, error) {\n", out.typ)
+	}
+
+	fmt.Fprintf(b, "\tr := request{method: %q, path: %s", method, g.pathExpr(path, params))
+	if hasParams(params, "query") {
+		b.WriteString(", query: url.Values{}")
+	}
+	if hasParams(params, "header") || hasParams(params, "cookie") {
+		b.WriteString(", header: http.Header{}")
+	}
+	if body != nil {
+		fmt.Fprintf(b, ", contentType: %q", bodyType)
+	}
+	b.WriteString("}\n")
+
+	for _, p := range params {
+		if p.In != "path" {
+			g.writeParam(b, p)
+		}
+	}
+	if body != nil {
+		if strings.HasPrefix(body.typ, "*") {
+			b.WriteString("\tif in.Body != nil {\n\t\tr.body = in.Body\n\t}\n")
+		} else {
+			b.WriteString("\tr.body = in.Body\n")
+		}
+	}
+
+	switch {
+	case out == nil:
+		b.WriteString("\treturn c.do(ctx, r, nil)\n")
+	case out.pointer:
+		fmt.Fprintf(b, "\tvar out %s\n", out.typ)
+		b.WriteString("\tif err := c.do(ctx, r, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n")
+	default:
+		fmt.Fprintf(b, "\tvar out %s\n", out.typ)
+		b.WriteString("\tif err := c.do(ctx, r, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n")
+	}
+	b.WriteString("}\n\n")
+	return nil
+}
+
+type paramInfo struct {
+	*parameter
+	field string
+	typ   string // Go type of the field
+	base  string // element type for slices, otherwise typ without pointer
+}
+
+// parameters merges path-level and operation-level parameters (the latter
+// win) in path, query, header, cookie order.
+func (g *generator) parameters(opName string, item *pathItem, op *operation) ([]*paramInfo, error) {
+	var merged []*parameter
+	for _, p := range slices.Concat(item.Parameters, op.Parameters) {
+		p, err := g.resolveParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		merged = slices.DeleteFunc(merged, func(q *parameter) bool { return q.In == p.In && q.Name == p.Name })
+		merged = append(merged, p)
+	}
+
+	order := map[string]int{"path": 0, "query": 1, "header": 2, "cookie": 3}
+	sort.SliceStable(merged, func(i, j int) bool { return order[merged[i].In] < order[merged[j].In] })
+
+	used := map[string]bool{"Body": true}
+	var params []*paramInfo
+	for _, p := range merged {
+		if _, ok := order[p.In]; !ok {
+			return nil, fmt.Errorf("parameter %q: unsupported location %q", p.Name, p.In)
+		}
+		field := goName(p.Name)
+		for i := 2; used[field]; i++ {
+			field = goName(p.Name) + strconv.Itoa(i)
+		}
+		used[field] = true
+
+		base := g.goType(p.Schema, opName+field)
+		typ := base
+		if strings.HasPrefix(base, "[]") {
+			base = strings.TrimPrefix(base, "[]")
+		} else if !p.Required && p.In != "path" {
+			typ = "*" + typ
+		}
+		params = append(params, &paramInfo{parameter: p, field: field, typ: typ, base: base})
+	}
+	return params, nil
+}
+
+func (g *generator) resolveParameter(p *parameter) (*parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := g.doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved parameter reference %q", p.Ref)
+	}
+	return resolved, nil
+}
+
+func hasParams(params []*paramInfo, in string) bool {
+	return slices.ContainsFunc(params, func(p *paramInfo) bool { return p.In == in })
+}
+
+// pathExpr builds the expression for the request path, escaping parameters.
+func (g *generator) pathExpr(path string, params []*paramInfo) string {
+	var parts []string
+	rest := path
+	for {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			break
+		}
+		if start > 0 {
+			parts = append(parts, strconv.Quote(rest[:start]))
+		}
+		name := rest[start+1 : end]
+		expr := strconv.Quote(rest[start : end+1])
+		for _, p := range params {
+			if p.In == "path" && p.Name == name {
+				expr = "url.PathEscape(" + g.formatValue(p.base, "in."+p.field) + ")"
+			}
+		}
+		parts = append(parts, expr)
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// writeParam emits code setting a query, header or cookie parameter.
+func (g *generator) writeParam(b *bytes.Buffer, p *paramInfo) {
+	var set func(value string) string
+	switch p.In {
+	case "query":
+		set = func(v string) string { return fmt.Sprintf("r.query.Add(%q, %s)", p.Name, v) }
+	case "header":
+		set = func(v string) string { return fmt.Sprintf("r.header.Add(%q, %s)", p.Name, v) }
+	case "cookie":
+		set = func(v string) string {
+			return fmt.Sprintf("r.header.Add(\"Cookie\", (&http.Cookie{Name: %q, Value: %s}).String())", p.Name, v)
+		}
+	}
+	field := "in." + p.field
+
+	switch {
+	case strings.HasPrefix(p.typ, "[]"):
+		// Query arrays explode into repeated parameters unless the spec says
+		// otherwise; headers and cookies use comma-separated values
+		if p.In == "query" && (p.Explode == nil || *p.Explode) {
+			fmt.Fprintf(b, "\tfor _, v := range %s {\n\t\t%s\n\t}\n", field, set(g.formatValue(p.base, "v")))
+			return
+		}
+		if p.base == "string" {
+			fmt.Fprintf(b, "\tif len(%s) > 0 {\n\t\t%s\n\t}\n", field, set("strings.Join("+field+`, ",")`))
+			return
+		}
+		fmt.Fprintf(b, "\tif len(%s) > 0 {\n", field)
+		fmt.Fprintf(b, "\t\tvalues := make([]string, 0, len(%s))\n", field)
+		fmt.Fprintf(b, "\t\tfor _, v := range %s {\n\t\t\tvalues = append(values, %s)\n\t\t}\n", field, g.formatValue(p.base, "v"))
+		fmt.Fprintf(b, "\t\t%s\n\t}\n", set(`strings.Join(values, ",")`))
+	case strings.HasPrefix(p.typ, "*"):
+		fmt.Fprintf(b, "\tif %s != nil {\n\t\t%s\n\t}\n", field, set(g.formatValue(p.base, "*"+field)))
+	default:
+		fmt.Fprintf(b, "\t%s\n", set(g.formatValue(p.base, field)))
+	}
+}
+
+// formatValue returns an expression converting expr of type typ to a string.
+func (g *generator) formatValue(typ, expr string) string {
+	switch typ {
+	case "string":
+		return expr
+	case "int64":
+		g.imports["strconv"] = true
+		return "strconv.FormatInt(" + expr + ", 10)"
+	case "int32":
+		g.imports["strconv"] = true
+		return "strconv.FormatInt(int64(" + expr + "), 10)"
+	case "float64":
+		g.imports["strconv"] = true
+		return "strconv.FormatFloat(" + expr + ", 'g', -1, 64)"
+	case "float32":
+		g.imports["strconv"] = true
+		return "strconv.FormatFloat(float64(" + expr + "), 'g', -1, 32)"
+	case "bool":
+		g.imports["strconv"] = true
+		return "strconv.FormatBool(" + expr + ")"
+	case "time.Time":
+		return expr + ".Format(time.RFC3339Nano)"
+	}
+	if u, ok := g.underlying[typ]; ok && u != "struct" && !strings.HasPrefix(u, "[]") && !strings.HasPrefix(u, "map[") {
+		return g.formatValue(u, u+"("+expr+")")
+	}
+	return "fmt.Sprint(" + expr + ")"
+}
+
+type bodyInfo struct {
+	schema   *schema
+	required bool
+	raw      bool   // sent as []byte
+	typ      string // Go type of the Body field
+}
+
+// requestBody picks the JSON content of the request body, falling back to
+// raw bytes for other media types.
+func (g *generator) requestBody(op *operation) (*bodyInfo, string, error) {
+	rb := op.RequestBody
+	if rb == nil {
+		return nil, "", nil
+	}
+	if rb.Ref != "" {
+		resolved, ok := g.doc.Components.RequestBodies[strings.TrimPrefix(rb.Ref, "#/components/requestBodies/")]
+		if !ok {
+			return nil, "", fmt.Errorf("unresolved request body reference %q", rb.Ref)
+		}
+		rb = resolved
+	}
+
+	contentType, media := pickContent(rb.Content)
+	if media == nil {
+		return nil, "", nil
+	}
+	return &bodyInfo{schema: media.Schema, required: rb.Required, raw: !isJSON(contentType)}, contentType, nil
+}
+
+type outputInfo struct {
+	typ     string
+	pointer bool
+}
+
+// responseBody returns the type of the first 2xx response with content.
+func (g *generator) responseBody(name string, op *operation) (*outputInfo, error) {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		resp := op.Responses[code]
+		if resp.Ref != "" {
+			resolved, ok := g.doc.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+			if !ok {
+				return nil, fmt.Errorf("unresolved response reference %q", resp.Ref)
+			}
+			resp = resolved
+		}
+
+		contentType, media := pickContent(resp.Content)
+		if media == nil {
+			continue
+		}
+		if !isJSON(contentType) {
+			return &outputInfo{typ: "[]byte"}, nil
+		}
+		typ := g.goType(media.Schema, name+"Response")
+		return &outputInfo{typ: typ, pointer: !g.nilable(typ)}, nil
+	}
+	return nil, nil
+}
+
+// pickContent prefers JSON media types, then the first in sorted order.
+func pickContent(content map[string]*mediaType) (string, *mediaType) {
+	keys := sortedKeys(content)
+	for _, k := range keys {
+		if isJSON(k) {
+			return k, content[k]
+		}
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+	return keys[0], content[keys[0]]
+}
+
+func isJSON(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// --- Helpers ---
+
+// commonInitialisms are upper-cased in generated names, as golint expects.
+var commonInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "JWT": true,
+	"SQL": true, "SSH": true, "TLS": true, "TTL": true, "UI": true, "URI": true,
+	"URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts an identifier such as "get-user", "user_id" or
+// "$schema" into an exported Go name ("GetUser", "UserID", "Schema").
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// writeDoc writes text as a comment, starting with prefix (e.g. "User: ").
+func writeDoc(b interface{ WriteString(string) (int, error) }, indent, prefix, text string, deprecated bool) {
+	text = strings.TrimSpace(text)
+	if text != "" {
+		for i, line := range strings.Split(text, "\n") {
+			line = strings.TrimRight(line, " \t")
+			if i == 0 {
+				line = prefix + line
+			}
+			if line == "" {
+				b.WriteString(indent + "//\n")
+				continue
+			}
+			b.WriteString(indent + "// " + line + "\n")
+		}
+	}
+	if deprecated {
+		if text != "" {
+			b.WriteString(indent + "//\n")
+		}
+		b.WriteString(indent + "// Deprecated: deprecated by the API.\n")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
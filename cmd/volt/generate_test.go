@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/widgets.json and internal/widgets/client.go")
+
+const (
+	widgetsSpecFile   = "testdata/widgets.json"
+	widgetsClientFile = "internal/widgets/client.go"
+)
+
+// --- Test API ---
+
+type Widget struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name" doc:"Display name"`
+	Color     string    `json:"color,omitempty" enum:"red,green,blue"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type getWidgetInput struct {
+	ID string `path:"id"`
+}
+
+type widgetOutput struct {
+	Body Widget
+}
+
+type listWidgetsInput struct {
+	Tag   []string `query:"tag"`
+	Limit int      `query:"limit" minimum:"1" maximum:"100"`
+	Trace string   `header:"X-Trace"`
+}
+
+type listWidgetsOutput struct {
+	Body []Widget
+}
+
+type createWidgetInput struct {
+	Body struct {
+		Name  string   `json:"name" minLength:"1"`
+		Color string   `json:"color,omitempty" enum:"red,green,blue"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+}
+
+var widgetCreatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newWidgetsApp builds the API the golden client is generated from.
+func newWidgetsApp() *volt.App {
+	app := volt.New(
+		volt.WithName("Widgets"),
+		volt.WithVersion("1.0.0"),
+		volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	volt.Register(app, volt.Operation{
+		Method:      "GET",
+		Path:        "/widgets/{id}",
+		OperationID: "get-widget",
+		Summary:     "Get a widget",
+	}, func(ctx context.Context, in *getWidgetInput) (*widgetOutput, error) {
+		if in.ID != "w1" {
+			return nil, volt.ErrNotFound("widget")
+		}
+		return &widgetOutput{Body: Widget{ID: "w1", Name: "Sprocket", CreatedAt: widgetCreatedAt}}, nil
+	})
+
+	volt.Register(app, volt.Operation{
+		Method:      "GET",
+		Path:        "/widgets",
+		OperationID: "list-widgets",
+		Summary:     "List widgets",
+	}, func(ctx context.Context, in *listWidgetsInput) (*listWidgetsOutput, error) {
+		out := &listWidgetsOutput{Body: []Widget{}}
+		for i := range max(in.Limit, 1) {
+			out.Body = append(out.Body, Widget{
+				ID:   "w" + string(rune('1'+i)),
+				Name: in.Trace,
+				Tags: in.Tag,
+			})
+		}
+		return out, nil
+	})
+
+	volt.Register(app, volt.Operation{
+		Method:      "POST",
+		Path:        "/widgets",
+		OperationID: "create-widget",
+		Summary:     "Create a widget",
+	}, func(ctx context.Context, in *createWidgetInput) (*widgetOutput, error) {
+		return &widgetOutput{Body: Widget{
+			ID:        "w2",
+			Name:      in.Body.Name,
+			Color:     in.Body.Color,
+			Tags:      in.Body.Tags,
+			CreatedAt: widgetCreatedAt,
+		}}, nil
+	})
+
+	volt.Register(app, volt.Operation{
+		Method:      "DELETE",
+		Path:        "/widgets/{id}",
+		OperationID: "delete-widget",
+		Summary:     "Delete a widget",
+	}, func(ctx context.Context, in *getWidgetInput) (*struct{}, error) {
+		return nil, nil
+	})
+
+	return app
+}
+
+// --- Tests ---
+
+// TestGoldenClient checks that testdata/widgets.json matches the test API
+// and internal/widgets/client.go matches the generator output. Run with
+// -update after changing either.
+func TestGoldenClient(t *testing.T) {
+	spec, err := json.MarshalIndent(newWidgetsApp().API().OpenAPI(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec = append(spec, '\n')
+
+	code, err := GenerateClient(spec, GenerateOptions{Package: "widgets", Source: widgetsSpecFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(widgetsSpecFile, spec, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(widgetsClientFile, code, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	for file, want := range map[string][]byte{widgetsSpecFile: spec, widgetsClientFile: code} {
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date; run go test ./cmd/volt -update", file)
+		}
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	t.Run("rejects swagger 2.0", func(t *testing.T) {
+		_, err := GenerateClient([]byte(`{"swagger": "2.0"}`), GenerateOptions{})
+		assertNotNil(t, err)
+	})
+
+	t.Run("schema features", func(t *testing.T) {
+		spec := `{
+			"openapi": "3.1.0",
+			"info": {"title": "Pets", "version": "2"},
+			"paths": {
+				"/pets/{pet_id}": {
+					"parameters": [{"name": "pet_id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int32"}}],
+					"get": {
+						"responses": {"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+					},
+					"put": {
+						"operationId": "replace_pet",
+						"requestBody": {"required": true, "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}},
+						"responses": {"204": {"description": "done"}}
+					}
+				}
+			},
+			"components": {"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["id", "owner"],
+					"properties": {
+						"id": {"type": "integer"},
+						"nickname": {"type": ["string", "null"]},
+						"owner": {"type": "object", "properties": {"name": {"type": "string"}}},
+						"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+						"kind": {"$ref": "#/components/schemas/Kind"}
+					}
+				},
+				"Kind": {"type": "string", "enum": ["cat", "dog"]}
+			}}
+		}`
+		code, err := GenerateClient([]byte(spec), GenerateOptions{Package: "pets"})
+		assertNil(t, err)
+
+		src := string(code)
+		for _, want := range []string{
+			"package pets",
+			"func (c *Client) GetPetsPetID(ctx context.Context, in *GetPetsPetIDInput) (*Pet, error)",
+			"func (c *Client) ReplacePet(ctx context.Context, in *ReplacePetInput) error",
+			"PetID int32",
+			"Body  []byte // application/octet-stream",
+			`ID       int64             ` + "`json:\"id\"`",
+			`Nickname *string           ` + "`json:\"nickname,omitempty\"`",
+			`Labels   map[string]string ` + "`json:\"labels,omitempty\"`",
+			"Owner    PetOwner",
+			"KindDog Kind = \"dog\"",
+			`url.PathEscape(strconv.FormatInt(int64(in.PetID), 10))`,
+		} {
+			if !strings.Contains(src, want) {
+				t.Errorf("generated code lacks %q:\n%s", want, src)
+			}
+		}
+	})
+}
+
+func TestGoName(t *testing.T) {
+	for in, want := range map[string]string{
+		"get-widget": "GetWidget",
+		"user_id":    "UserID",
+		"$schema":    "Schema",
+		"X-Trace":    "XTrace",
+		"2fa":        "X2fa",
+		"apiUrl":     "ApiUrl",
+		"":           "X",
+	} {
+		assertEqual(t, want, goName(in))
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := t.TempDir() + "/widgets/client.go"
+	var stderr bytes.Buffer
+	assertNil(t, run([]string{"generate", "client", "-spec", widgetsSpecFile, "-out", out}, io.Discard, &stderr))
+
+	code, err := os.ReadFile(out)
+	assertNil(t, err)
+	assertTrue(t, bytes.Contains(code, []byte("package widgets\n")))
+
+	assertNotNil(t, run([]string{"generate", "server"}, io.Discard, &stderr))
+}
+
+// --- Helpers ---
+
+func assertEqual[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func assertTrue(t *testing.T, condition bool) {
+	t.Helper()
+	if !condition {
+		t.Error("expected true, got false")
+	}
+}
+
+func assertNil(t *testing.T, v any) {
+	t.Helper()
+	if err, ok := v.(error); ok && err != nil {
+		t.Errorf("expected nil, got error: %v", err)
+	}
+}
+
+func assertNotNil(t *testing.T, v any) {
+	t.Helper()
+	if v == nil {
+		t.Error("expected non-nil, got nil")
+	}
+}
@@ -0,0 +1,261 @@
+// Code generated by volt generate client from testdata/widgets.json. DO NOT EDIT.
+
+// Package widgets is a client for Widgets API (version 1.0.0).
+package widgets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+// Client calls the API over an *http.Client.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// New creates a client that sends path-only request URLs, which the
+// *http.Client resolves against the service. Its signature matches the
+// volt.RegisterHTTPService factory:
+//
+//	volt.RegisterHTTPService(app, "name", New, volt.WithHTTPBaseURL("http://service:8080"))
+func New(httpClient *http.Client) *Client {
+	return &Client{httpClient: httpClient}
+}
+
+// NewWithBaseURL returns a factory for clients that send requests to
+// baseURL, for use with a plain *http.Client.
+func NewWithBaseURL(baseURL string) func(httpClient *http.Client) *Client {
+	return func(httpClient *http.Client) *Client {
+		return &Client{httpClient: httpClient, baseURL: strings.TrimSuffix(baseURL, "/")}
+	}
+}
+
+// Ptr returns a pointer to v, for optional fields and parameters.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        any // encoded as JSON unless []byte
+	contentType string
+}
+
+func (c *Client) do(ctx context.Context, r request, out any) error {
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	var body io.Reader
+	switch b := r.body.(type) {
+	case nil:
+	case []byte:
+		body = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("encoding %s %s request: %w", r.method, r.path, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return volt.ErrorFromResponse(resp)
+	}
+
+	switch o := out.(type) {
+	case nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+	case *[]byte:
+		if *o, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf("reading %s %s response: %w", r.method, r.path, err)
+		}
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decoding %s %s response: %w", r.method, r.path, err)
+		}
+	}
+	return nil
+}
+
+// ListWidgetsInput holds the parameters of ListWidgets.
+type ListWidgetsInput struct {
+	Tag    []string // query "tag"
+	Limit  *int64   // query "limit"
+	XTrace *string  // header "X-Trace"
+}
+
+// ListWidgets calls GET /widgets.
+//
+// List widgets
+func (c *Client) ListWidgets(ctx context.Context, in *ListWidgetsInput) ([]Widget, error) {
+	r := request{method: "GET", path: "/widgets", query: url.Values{}, header: http.Header{}}
+	if len(in.Tag) > 0 {
+		r.query.Add("tag", strings.Join(in.Tag, ","))
+	}
+	if in.Limit != nil {
+		r.query.Add("limit", strconv.FormatInt(*in.Limit, 10))
+	}
+	if in.XTrace != nil {
+		r.header.Add("X-Trace", *in.XTrace)
+	}
+	var out []Widget
+	if err := c.do(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWidgetInput holds the parameters of CreateWidget.
+type CreateWidgetInput struct {
+	Body CreateWidgetInputBody // application/json
+}
+
+// CreateWidget calls POST /widgets.
+//
+// Create a widget
+func (c *Client) CreateWidget(ctx context.Context, in *CreateWidgetInput) (*Widget, error) {
+	r := request{method: "POST", path: "/widgets", contentType: "application/json"}
+	r.body = in.Body
+	var out Widget
+	if err := c.do(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWidgetInput holds the parameters of GetWidget.
+type GetWidgetInput struct {
+	ID string // path "id"
+}
+
+// GetWidget calls GET /widgets/{id}.
+//
+// Get a widget
+func (c *Client) GetWidget(ctx context.Context, in *GetWidgetInput) (*Widget, error) {
+	r := request{method: "GET", path: "/widgets/" + url.PathEscape(in.ID)}
+	var out Widget
+	if err := c.do(ctx, r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWidgetInput holds the parameters of DeleteWidget.
+type DeleteWidgetInput struct {
+	ID string // path "id"
+}
+
+// DeleteWidget calls DELETE /widgets/{id}.
+//
+// Delete a widget
+func (c *Client) DeleteWidget(ctx context.Context, in *DeleteWidgetInput) error {
+	r := request{method: "DELETE", path: "/widgets/" + url.PathEscape(in.ID)}
+	return c.do(ctx, r, nil)
+}
+
+// --- Types ---
+
+// CreateWidgetInputBody is the CreateWidgetInputBody schema.
+type CreateWidgetInputBody struct {
+	// A URL to the JSON Schema for this object.
+	Schema *string                     `json:"$schema,omitempty"`
+	Color  *CreateWidgetInputBodyColor `json:"color,omitempty"`
+	Name   string                      `json:"name"`
+	Tags   []string                    `json:"tags,omitempty"`
+}
+
+// CreateWidgetInputBodyColor is an enumerated string; see the constants below.
+type CreateWidgetInputBodyColor string
+
+const (
+	CreateWidgetInputBodyColorRed   CreateWidgetInputBodyColor = "red"
+	CreateWidgetInputBodyColorGreen CreateWidgetInputBodyColor = "green"
+	CreateWidgetInputBodyColorBlue  CreateWidgetInputBodyColor = "blue"
+)
+
+// ErrorDetail is the ErrorDetail schema.
+type ErrorDetail struct {
+	// Where the error occurred, e.g. 'body.items[3].tags' or 'path.thing-id'
+	Location *string `json:"location,omitempty"`
+	// Error message text
+	Message *string `json:"message,omitempty"`
+	// The value at the given location
+	Value any `json:"value,omitempty"`
+}
+
+// ErrorModel is the ErrorModel schema.
+type ErrorModel struct {
+	// A URL to the JSON Schema for this object.
+	Schema *string `json:"$schema,omitempty"`
+	// Machine-readable error code
+	Code *string `json:"code,omitempty"`
+	// A human-readable explanation specific to this occurrence
+	Detail *string `json:"detail,omitempty"`
+	// Individual error details
+	Errors []ErrorDetail `json:"errors,omitempty"`
+	// A URI reference identifying this occurrence
+	Instance *string `json:"instance,omitempty"`
+	// HTTP status code
+	Status *int64 `json:"status,omitempty"`
+	// A short, human-readable summary of the problem type
+	Title *string `json:"title,omitempty"`
+	// Trace ID for debugging
+	TraceID *string `json:"trace_id,omitempty"`
+	// A URI reference identifying the problem type
+	Type *string `json:"type,omitempty"`
+}
+
+// Widget is the Widget schema.
+type Widget struct {
+	// A URL to the JSON Schema for this object.
+	Schema    *string      `json:"$schema,omitempty"`
+	Color     *WidgetColor `json:"color,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	ID        string       `json:"id"`
+	// Display name
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// WidgetColor is an enumerated string; see the constants below.
+type WidgetColor string
+
+const (
+	WidgetColorRed   WidgetColor = "red"
+	WidgetColorGreen WidgetColor = "green"
+	WidgetColorBlue  WidgetColor = "blue"
+)
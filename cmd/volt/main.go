@@ -0,0 +1,120 @@
+// Command volt is the Volt developer tool.
+//
+// Usage:
+//
+//	volt generate client -spec http://billing:8080/openapi.json -package billing -out billing/client.go
+//
+// The generated package exposes New(*http.Client) *Client, which plugs
+// straight into volt.RegisterHTTPService:
+//
+//	volt.RegisterHTTPService(app, "billing", billing.New,
+//	    volt.WithHTTPBaseURL("http://billing:8080"),
+//	)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const usage = `Usage: volt <command> [flags]
+
+Commands:
+  generate client   Generate a Go client package from an OpenAPI 3.1 document
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "volt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) >= 2 && args[0] == "generate" && args[1] == "client" {
+		return generateClientCommand(args[2:], stdout, stderr)
+	}
+	fmt.Fprint(stderr, usage)
+	return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+}
+
+func generateClientCommand(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("volt generate client", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	spec := flags.String("spec", "", "OpenAPI document: file path or http(s) URL (required)")
+	pkg := flags.String("package", "", "Go package name (default: derived from -out, or \"client\")")
+	out := flags.String("out", "", "output file (default: stdout)")
+	voltImport := flags.String("volt", defaultVoltImport, "import path of the volt package")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *spec == "" {
+		flags.Usage()
+		return fmt.Errorf("-spec is required")
+	}
+
+	if *pkg == "" {
+		*pkg = "client"
+		if *out != "" {
+			if dir := filepath.Base(filepath.Dir(*out)); dir != "." && dir != string(filepath.Separator) {
+				*pkg = dir
+			}
+		}
+	}
+
+	data, err := readSpec(*spec)
+	if err != nil {
+		return err
+	}
+
+	code, err := GenerateClient(data, GenerateOptions{
+		Package:    *pkg,
+		VoltImport: *voltImport,
+		Source:     *spec,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(code)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, code, 0o644)
+}
+
+// readSpec loads the document from a file or, for http(s) sources, from a
+// running service (e.g. its /openapi.json).
+func readSpec(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching spec: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching spec: %s returned %d", source, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -1,6 +1,7 @@
 package volt
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"os"
@@ -17,6 +18,16 @@ type Config struct {
 	Server  ServerConfig
 	OTEL    OTELConfig
 	OpenAPI OpenAPIConfig
+	Authz   AuthzConfig
+	Health  HealthConfig
+
+	// Translators for errors returned by handlers, tried in order before
+	// the built-in ones (sql.ErrNoRows: 404, deadline exceeded: 504,
+	// canceled: 499)
+	ErrorTranslators []ErrorTranslator
+
+	// Localized error messages, negotiated from Accept-Language
+	Messages *MessageBundle
 
 	Logger *slog.Logger
 }
@@ -30,6 +41,10 @@ type ServerConfig struct {
 	IdleTimeout     time.Duration
 	RequestTimeout  time.Duration
 	ShutdownTimeout time.Duration
+
+	// Time between readiness turning false and the server shutting down,
+	// so load balancers stop sending traffic first (default: 0)
+	DrainDelay time.Duration
 }
 
 // OTELConfig holds OpenTelemetry configuration.
@@ -38,7 +53,24 @@ type OTELConfig struct {
 	ServiceName    string
 	ServiceVersion string
 	Environment    string
-	CollectorURL   string // gRPC endpoint, e.g., "localhost:4317"
+	CollectorURL   string // OTLP endpoint, e.g., "localhost:4317" or "https://collector:4318"
+
+	// Exporter: ExporterOTLP or ExporterStdout. Default: stdout in
+	// development without a collector URL, OTLP otherwise.
+	Exporter string
+
+	// OTLP transport: OTLPProtocolGRPC (default) or OTLPProtocolHTTP
+	Protocol string
+
+	// TLS for the collector connection. Default: plaintext, unless the
+	// collector URL is https or OTEL_EXPORTER_OTLP_CERTIFICATE is set.
+	TLSConfig *tls.Config
+
+	// Headers sent with every export, e.g. for authentication
+	Headers map[string]string
+
+	// Compression: "gzip" or "none" (default: exporter default)
+	Compression string
 
 	// Sampling configuration
 	TraceSampleRate float64
@@ -50,6 +82,13 @@ type OTELConfig struct {
 	EnableTraces  bool
 	EnableMetrics bool
 	EnableLogs    bool
+
+	// Serve metrics for Prometheus scrapes next to the push exporter
+	Prometheus     bool
+	PrometheusPath string // Default: /metrics
+
+	// Export to memory instead of the collector, for tests
+	InMemory *InMemoryExporter
 }
 
 // OpenAPIConfig holds OpenAPI documentation configuration.
@@ -61,6 +100,24 @@ type OpenAPIConfig struct {
 	SecurityDef map[string]SecurityScheme
 }
 
+// AuthzConfig holds authorization enforcement configuration.
+type AuthzConfig struct {
+	// FailOpen lets requests through to operations that declare an
+	// AuthzRequirement when no AuthzPolicy has been set.
+	// Default: false - such requests are rejected with a 500.
+	FailOpen bool
+}
+
+// HealthConfig holds health check execution settings.
+type HealthConfig struct {
+	// Timeout for each check unless the check sets its own (default: 2s)
+	Timeout time.Duration
+
+	// How long a check result is reused before the check runs again.
+	// Default: 1s. Negative disables caching.
+	CacheTTL time.Duration
+}
+
 // OpenAPIServer represents an API server for the OpenAPI spec.
 type OpenAPIServer struct {
 	URL         string
@@ -99,7 +156,8 @@ func DefaultConfig() *Config {
 			ServiceName:     getEnv("OTEL_SERVICE_NAME", "volt-app"),
 			ServiceVersion:  getEnv("OTEL_SERVICE_VERSION", "0.0.1"),
 			Environment:     getEnv("OTEL_ENVIRONMENT", "development"),
-			CollectorURL:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
+			CollectorURL:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			Protocol:        getEnv("OTEL_EXPORTER_OTLP_PROTOCOL", OTLPProtocolGRPC),
 			TraceSampleRate: 1.0,
 			EnableTraces:    true,
 			EnableMetrics:   true,
@@ -112,6 +170,11 @@ func DefaultConfig() *Config {
 			SpecPath: "/openapi.json",
 		},
 
+		Health: HealthConfig{
+			Timeout:  2 * time.Second,
+			CacheTTL: time.Second,
+		},
+
 		Logger: slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		})),
@@ -174,6 +237,75 @@ func WithOTELCollector(url string) Option {
 	}
 }
 
+// WithOTELProtocol selects the OTLP transport: OTLPProtocolGRPC or
+// OTLPProtocolHTTP.
+func WithOTELProtocol(protocol string) Option {
+	return func(c *Config) {
+		c.OTEL.Protocol = protocol
+	}
+}
+
+// WithOTELTLS sets the TLS configuration for the collector connection,
+// e.g. with a custom CA pool and client certificates.
+func WithOTELTLS(tlsConfig *tls.Config) Option {
+	return func(c *Config) {
+		c.OTEL.TLSConfig = tlsConfig
+	}
+}
+
+// WithOTELHeaders sets headers sent with every export.
+func WithOTELHeaders(headers map[string]string) Option {
+	return func(c *Config) {
+		c.OTEL.Headers = headers
+	}
+}
+
+// WithOTELStdout enables OTEL with all signals pretty-printed to stdout.
+func WithOTELStdout() Option {
+	return func(c *Config) {
+		c.OTEL.Enabled = true
+		c.OTEL.Exporter = ExporterStdout
+	}
+}
+
+// WithOTELPrometheus serves the app's metrics in the Prometheus format at
+// path (default: /metrics), in addition to pushing them over OTLP. The
+// endpoint is not part of the OpenAPI spec and its requests are not logged.
+func WithOTELPrometheus(path string) Option {
+	return func(c *Config) {
+		c.OTEL.Enabled = true
+		c.OTEL.Prometheus = true
+		c.OTEL.PrometheusPath = path
+	}
+}
+
+// WithOTELInMemory enables OTEL with all signals exported to mem, so tests
+// can query the spans, metrics and logs the app produces. An exporter
+// serves a single app.
+func WithOTELInMemory(mem *InMemoryExporter) Option {
+	return func(c *Config) {
+		c.OTEL.Enabled = true
+		c.OTEL.InMemory = mem
+	}
+}
+
+// WithErrorTranslators adds translators that map errors returned by
+// handlers to *Error responses. They run in order, before the built-in
+// translators.
+func WithErrorTranslators(translators ...ErrorTranslator) Option {
+	return func(c *Config) {
+		c.ErrorTranslators = append(c.ErrorTranslators, translators...)
+	}
+}
+
+// WithMessageBundle localizes error messages to the language negotiated
+// from each request's Accept-Language header.
+func WithMessageBundle(messages *MessageBundle) Option {
+	return func(c *Config) {
+		c.Messages = messages
+	}
+}
+
 // WithLogger sets a custom logger.
 func WithLogger(logger *slog.Logger) Option {
 	return func(c *Config) {
@@ -188,6 +320,37 @@ func WithOpenAPIServers(servers ...OpenAPIServer) Option {
 	}
 }
 
+// WithAuthzFailOpen allows operations that declare authorization requirements
+// to be served when no AuthzPolicy is configured. Intended for development only.
+func WithAuthzFailOpen() Option {
+	return func(c *Config) {
+		c.Authz.FailOpen = true
+	}
+}
+
+// WithHealthCheckTimeout sets the default per-check health check timeout.
+func WithHealthCheckTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.Health.Timeout = d
+	}
+}
+
+// WithHealthCacheTTL sets how long health check results are cached.
+// A negative value runs every check on every request.
+func WithHealthCacheTTL(d time.Duration) Option {
+	return func(c *Config) {
+		c.Health.CacheTTL = d
+	}
+}
+
+// WithDrainDelay sets how long Shutdown waits after failing readiness
+// before it stops the server.
+func WithDrainDelay(d time.Duration) Option {
+	return func(c *Config) {
+		c.Server.DrainDelay = d
+	}
+}
+
 // WithRequestTimeout sets the request timeout.
 func WithRequestTimeout(d time.Duration) Option {
 	return func(c *Config) {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"reflect"
 
+	"github.com/go-chi/chi/v5/middleware"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -17,31 +19,45 @@ type Context struct {
 }
 
 // Use retrieves a typed service from the registry.
-// Panics if the service is not found or has the wrong type.
+// Panics with a *UseError if the service is not found or has the wrong
+// type; the panic is recovered and logged like any other.
 //
 // Example:
 //
 //	gitlab := volt.Use[*gitlab.Client](ctx, "gitlab")
 func Use[T any](ctx context.Context, name string) T {
+	want := reflect.TypeFor[T]().String()
 	voltCtx, ok := ctx.(*Context)
 	if !ok {
-		panic("Use called with non-Volt context")
+		panic(&UseError{Service: name, Type: want,
+			Reason: "called with non-Volt context; pass the handler's context itself, not one derived from it"})
 	}
 
 	svc, ok := voltCtx.registry.Get(name)
 	if !ok {
-		panic("service not found: " + name)
+		panic(&UseError{Service: name, Type: want, Reason: "service not found; register it before starting the app"})
 	}
 
 	typed, ok := svc.(T)
 	if !ok {
-		var zero T
-		panic("service type mismatch for " + name + ": expected " + typeName(zero))
+		panic(&UseError{Service: name, Type: want, Reason: "service type mismatch: registered as " + typeName(svc)})
 	}
 
 	return typed
 }
 
+// UseError is the panic value of Use when a service can't be provided.
+type UseError struct {
+	Service string
+	Type    string // type argument of Use
+	Reason  string
+}
+
+// Error implements the error interface.
+func (e *UseError) Error() string {
+	return fmt.Sprintf("volt.Use[%s](%q): %s", e.Type, e.Service, e.Reason)
+}
+
 // TryUse retrieves a typed service from the registry.
 // Returns (zero value, false) if the service is not found or has the wrong type.
 //
@@ -119,6 +135,10 @@ type contextKey int
 const (
 	requestIDKey contextKey = iota
 	userKey
+	inboundHeadersKey
+	requestPathKey
+	localeKey
+	errorCatalogKey
 )
 
 // WithRequestID adds a request ID to the context.
@@ -126,12 +146,13 @@ func WithRequestID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, requestIDKey, id)
 }
 
-// RequestID retrieves the request ID from context.
+// RequestID retrieves the request ID from context, as set by WithRequestID
+// or chi's middleware.RequestID.
 func RequestID(ctx context.Context) string {
 	if id, ok := ctx.Value(requestIDKey).(string); ok {
 		return id
 	}
-	return ""
+	return middleware.GetReqID(ctx)
 }
 
 // WithUser adds user information to the context.
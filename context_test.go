@@ -2,6 +2,7 @@ package volt
 
 import (
 	"context"
+	"fmt"
 	"testing"
 )
 
@@ -30,9 +31,11 @@ func TestUse(t *testing.T) {
 			if r == nil {
 				t.Error("expected panic")
 			}
-			if r != "Use called with non-Volt context" {
-				t.Errorf("unexpected panic message: %v", r)
+			err, ok := r.(*UseError)
+			if !ok {
+				t.Fatalf("expected *UseError, got %T", r)
 			}
+			assertEqual(t, `volt.Use[*volt.TestService]("myservice"): called with non-Volt context; pass the handler's context itself, not one derived from it`, err.Error())
 		}()
 
 		Use[*TestService](context.Background(), "myservice")
@@ -50,6 +53,7 @@ func TestUse(t *testing.T) {
 			if r == nil {
 				t.Error("expected panic")
 			}
+			assertEqual(t, `volt.Use[*volt.TestService]("unknown"): service not found; register it before starting the app`, fmt.Sprint(r))
 		}()
 
 		Use[*TestService](ctx, "unknown")
@@ -69,6 +73,7 @@ func TestUse(t *testing.T) {
 			if r == nil {
 				t.Error("expected panic")
 			}
+			assertEqual(t, `volt.Use[*volt.TestService]("myservice"): service type mismatch: registered as string`, fmt.Sprint(r))
 		}()
 
 		Use[*TestService](ctx, "myservice")
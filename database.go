@@ -0,0 +1,610 @@
+package volt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// --- Database Instrumentation ---
+//
+// Registered databases are opened through a connector that wraps the
+// driver's connections. Every query, exec and transaction becomes a client
+// span carrying db.system, the sanitized statement and the number of rows
+// returned or affected. Query spans stay open until the rows are closed, so
+// their duration includes reading the results.
+
+// openInstrumentedDB opens a *sql.DB whose connections emit spans.
+func openInstrumentedDB(driverName, dsn, system string, tracer trace.Tracer) (*sql.DB, error) {
+	// sql.Open only resolves the driver; it does not connect
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	db.Close()
+
+	var connector driver.Connector
+	if dc, ok := drv.(driver.DriverContext); ok {
+		connector, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		connector = &dsnConnector{dsn: dsn, driver: drv}
+	}
+
+	if system == "" {
+		system = dbSystemFromDriver(driverName)
+	}
+
+	return sql.OpenDB(&instrumentedConnector{
+		base: connector,
+		inst: &dbInstrumentation{
+			tracer: tracer,
+			attrs:  []attribute.KeyValue{semconv.DBSystemKey.String(system)},
+		},
+	}), nil
+}
+
+// dbSystemFromDriver maps common driver names to db.system values.
+func dbSystemFromDriver(driverName string) string {
+	switch name := strings.ToLower(driverName); {
+	case strings.HasPrefix(name, "postgres"), strings.HasPrefix(name, "pgx"):
+		return "postgresql"
+	case name == "mysql":
+		return "mysql"
+	case strings.HasPrefix(name, "sqlite"):
+		return "sqlite"
+	case name == "sqlserver", name == "mssql":
+		return "mssql"
+	case name == "oracle", name == "godror", name == "oci8":
+		return "oracle"
+	case name == "clickhouse":
+		return "clickhouse"
+	default:
+		return "other_sql"
+	}
+}
+
+// registerDBStatsMetrics reports sql.DBStats as observable instruments.
+// Unregister the returned registration before closing the database.
+func registerDBStatsMetrics(db *sql.DB, meter metric.Meter, name string) (metric.Registration, error) {
+	attrs := metric.WithAttributes(semconv.DBClientConnectionsPoolNameKey.String(name))
+
+	open, err := meter.Int64ObservableGauge("db.client.connections.open",
+		metric.WithDescription("Number of established connections, both in use and idle"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := meter.Int64ObservableGauge("db.client.connections.in_use",
+		metric.WithDescription("Number of connections currently in use"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	idle, err := meter.Int64ObservableGauge("db.client.connections.idle",
+		metric.WithDescription("Number of idle connections"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	waitCount, err := meter.Int64ObservableCounter("db.client.connections.wait_count",
+		metric.WithDescription("Total number of connections waited for"),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	waitDuration, err := meter.Float64ObservableCounter("db.client.connections.wait_duration",
+		metric.WithDescription("Total time blocked waiting for a new connection"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+		o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+		o.ObserveInt64(idle, int64(stats.Idle), attrs)
+		o.ObserveInt64(waitCount, stats.WaitCount, attrs)
+		o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), attrs)
+		return nil
+	}, open, inUse, idle, waitCount, waitDuration)
+}
+
+// --- Spans ---
+
+type dbInstrumentation struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+// startSpan starts a client span for a statement. Spans are started after
+// the driver call returns (with the call's start time) so that calls the
+// driver skips with driver.ErrSkip never produce a span.
+func (i *dbInstrumentation) startSpan(ctx context.Context, start time.Time, query string) trace.Span {
+	op := sqlOperation(query)
+	name := op
+	if name == "" {
+		name = "db.query"
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(i.attrs)+2)
+	attrs = append(attrs, i.attrs...)
+	attrs = append(attrs, semconv.DBQueryTextKey.String(sanitizeSQL(query)))
+	if op != "" {
+		attrs = append(attrs, semconv.DBOperationNameKey.String(op))
+	}
+
+	_, span := i.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attrs...),
+	)
+	return span
+}
+
+// endSpan records err (if any) and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordExec records an exec result on a span and ends it.
+func (i *dbInstrumentation) recordExec(ctx context.Context, start time.Time, query string, res driver.Result, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	span := i.startSpan(ctx, start, query)
+	if err == nil {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(attribute.Int64("db.response.affected_rows", n))
+		}
+	}
+	endSpan(span, err)
+}
+
+// recordQuery starts a span for a query. On success the span is handed to
+// the returned rows and ends when they are closed.
+func (i *dbInstrumentation) recordQuery(ctx context.Context, start time.Time, query string, rows driver.Rows, err error) (driver.Rows, error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+	span := i.startSpan(ctx, start, query)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, span: span}, nil
+}
+
+// sqlOperation returns the leading SQL keyword (e.g. SELECT), or "" if none.
+func sqlOperation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexFunc(query, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(query)
+	}
+	return strings.ToUpper(query[:end])
+}
+
+// sanitizeSQL replaces string and numeric literals with ?, drops comments
+// and collapses whitespace, so statements can be recorded without leaking
+// values. Placeholders such as $1 and :name are kept.
+func sanitizeSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	isIdent := func(c byte) bool {
+		return c == '_' || c == '$' || c == ':' || c == '@' ||
+			c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+
+	var prev byte
+	space := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'':
+			// String literal; '' is an escaped quote
+			i++
+			for i < len(query) {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			c = '?'
+		case c >= '0' && c <= '9' && !isIdent(prev):
+			// Numeric literal, including decimals and hex
+			for i < len(query) && (isIdent(query[i]) || query[i] == '.') {
+				i++
+			}
+			c = '?'
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			space, prev = true, ' '
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(query)
+			}
+			space, prev = true, ' '
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			space, prev = true, ' '
+			continue
+		default:
+			i++
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+		prev = c
+	}
+
+	return b.String()
+}
+
+// --- Driver Wrappers ---
+
+// dsnConnector adapts a driver without DriverContext to driver.Connector.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type instrumentedConnector struct {
+	base driver.Connector
+	inst *dbInstrumentation
+}
+
+func (c *instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn, inst: c.inst}, nil
+}
+
+func (c *instrumentedConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// Close closes the underlying connector if it supports it.
+func (c *instrumentedConnector) Close() error {
+	if closer, ok := c.base.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// instrumentedConn implements every optional connection interface and
+// falls back to database/sql's default behavior when the wrapped driver
+// doesn't support one.
+type instrumentedConn struct {
+	driver.Conn
+	inst *dbInstrumentation
+}
+
+var (
+	_ driver.ExecerContext      = (*instrumentedConn)(nil)
+	_ driver.QueryerContext     = (*instrumentedConn)(nil)
+	_ driver.ConnPrepareContext = (*instrumentedConn)(nil)
+	_ driver.ConnBeginTx        = (*instrumentedConn)(nil)
+	_ driver.Pinger             = (*instrumentedConn)(nil)
+	_ driver.NamedValueChecker  = (*instrumentedConn)(nil)
+	_ driver.SessionResetter    = (*instrumentedConn)(nil)
+	_ driver.Validator          = (*instrumentedConn)(nil)
+)
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		// database/sql falls back to a prepared statement, which is instrumented
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args)
+	c.inst.recordExec(ctx, start, query, res, err)
+	return res, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	return c.inst.recordQuery(ctx, start, query, rows, err)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{Stmt: stmt, query: query, inst: c.inst}, nil
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		err = errors.New("volt: driver does not support transaction options")
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck // fallback for legacy drivers
+	}
+
+	_, span := c.inst.tracer.Start(ctx, "db.transaction",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(c.inst.attrs...),
+	)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	return &instrumentedTx{Tx: tx, span: span}, nil
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type instrumentedStmt struct {
+	driver.Stmt
+	query string
+	inst  *dbInstrumentation
+}
+
+var (
+	_ driver.StmtExecContext   = (*instrumentedStmt)(nil)
+	_ driver.StmtQueryContext  = (*instrumentedStmt)(nil)
+	_ driver.NamedValueChecker = (*instrumentedStmt)(nil)
+)
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var res driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values) //nolint:staticcheck // fallback for legacy drivers
+		}
+	}
+
+	s.inst.recordExec(ctx, start, s.query, res, err)
+	return res, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values) //nolint:staticcheck // fallback for legacy drivers
+		}
+	}
+
+	return s.inst.recordQuery(ctx, start, s.query, rows, err)
+}
+
+func (s *instrumentedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(named []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(named))
+	for i, nv := range named {
+		if nv.Name != "" {
+			return nil, errors.New("volt: driver does not support named parameters")
+		}
+		values[i] = nv.Value
+	}
+	return values, nil
+}
+
+// instrumentedRows counts rows as they are read and ends the query span on Close.
+type instrumentedRows struct {
+	driver.Rows
+	span  trace.Span
+	count int64
+	err   error
+	once  sync.Once
+}
+
+var (
+	_ driver.RowsNextResultSet              = (*instrumentedRows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*instrumentedRows)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*instrumentedRows)(nil)
+	_ driver.RowsColumnTypeLength           = (*instrumentedRows)(nil)
+	_ driver.RowsColumnTypeNullable         = (*instrumentedRows)(nil)
+	_ driver.RowsColumnTypePrecisionScale   = (*instrumentedRows)(nil)
+)
+
+func (r *instrumentedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.count++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *instrumentedRows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		r.span.SetAttributes(attribute.Int64("db.response.returned_rows", r.count))
+		if err == nil {
+			err = r.err
+		}
+		endSpan(r.span, err)
+	})
+	return err
+}
+
+func (r *instrumentedRows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *instrumentedRows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *instrumentedRows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r *instrumentedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *instrumentedRows) ColumnTypeLength(index int) (int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *instrumentedRows) ColumnTypeNullable(index int) (bool, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *instrumentedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+// instrumentedTx ends the transaction span on Commit or Rollback.
+type instrumentedTx struct {
+	driver.Tx
+	span trace.Span
+}
+
+func (t *instrumentedTx) Commit() error {
+	err := t.Tx.Commit()
+	t.span.SetAttributes(attribute.String("db.transaction.outcome", "commit"))
+	endSpan(t.span, err)
+	return err
+}
+
+func (t *instrumentedTx) Rollback() error {
+	err := t.Tx.Rollback()
+	t.span.SetAttributes(attribute.String("db.transaction.outcome", "rollback"))
+	endSpan(t.span, err)
+	return err
+}
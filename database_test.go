@@ -0,0 +1,273 @@
+package volt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeDriver returns three rows for any query and reports one affected row
+// for any exec. Queries containing "fail" return an error. The legacy
+// variant only implements the pre-context interfaces, forcing database/sql
+// through prepared statements.
+type fakeDriver struct{ legacy bool }
+
+func init() {
+	sql.Register("volt-fake", fakeDriver{})
+	sql.Register("volt-fake-legacy", fakeDriver{legacy: true})
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	if d.legacy {
+		return &fakeLegacyConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+var errFakeQuery = errors.New("fake query failed")
+
+type fakeLegacyConn struct{}
+
+func (c *fakeLegacyConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+func (c *fakeLegacyConn) Close() error              { return nil }
+func (c *fakeLegacyConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeConn struct{ fakeLegacyConn }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return (&fakeStmt{query: query}).Query(nil)
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return (&fakeStmt{query: query}).Exec(nil)
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeStmt struct{ query string }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errFakeQuery
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errFakeQuery
+	}
+	return &fakeRows{remaining: 3}, nil
+}
+
+type fakeRows struct{ remaining int }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	dest[0] = int64(r.remaining)
+	r.remaining--
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+// newTestDB registers and initializes a database on an app whose telemetry
+// is captured in memory.
+func newTestDB(t *testing.T, driverName string) (*sql.DB, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	app := newTestApp()
+	app.otel = &OTELProvider{
+		tracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		meterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+
+	config := DefaultDatabaseConfig()
+	config.Driver = driverName
+	RegisterDatabase(app, "primary", config)
+
+	ctx := context.Background()
+	if err := app.registry.Initialize(ctx, app); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	t.Cleanup(func() { app.registry.Shutdown(ctx) })
+
+	return app.registry.MustGet("primary").(*sql.DB), recorder, reader
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestInstrumentedDB(t *testing.T) {
+	ctx := context.Background()
+
+	for _, driverName := range []string{"volt-fake", "volt-fake-legacy"} {
+		t.Run(driverName, func(t *testing.T) {
+			t.Run("query span counts returned rows", func(t *testing.T) {
+				db, recorder, _ := newTestDB(t, driverName)
+
+				rows, err := db.QueryContext(ctx, "SELECT id FROM users WHERE name = 'alice' AND age > 30")
+				assertNil(t, err)
+				n := 0
+				for rows.Next() {
+					n++
+				}
+				assertNil(t, rows.Close())
+				assertEqual(t, 3, n)
+
+				spans := recorder.Ended()
+				assertEqual(t, 1, len(spans))
+				span := spans[0]
+				assertEqual(t, "SELECT", span.Name())
+
+				system, _ := spanAttr(span, "db.system")
+				assertEqual(t, "other_sql", system.AsString())
+				text, _ := spanAttr(span, "db.query.text")
+				assertEqual(t, "SELECT id FROM users WHERE name = ? AND age > ?", text.AsString())
+				returned, _ := spanAttr(span, "db.response.returned_rows")
+				assertEqual(t, int64(3), returned.AsInt64())
+			})
+
+			t.Run("exec span records affected rows", func(t *testing.T) {
+				db, recorder, _ := newTestDB(t, driverName)
+
+				_, err := db.ExecContext(ctx, "UPDATE users SET active = true WHERE id = $1", 42)
+				assertNil(t, err)
+
+				spans := recorder.Ended()
+				assertEqual(t, 1, len(spans))
+				assertEqual(t, "UPDATE", spans[0].Name())
+				affected, _ := spanAttr(spans[0], "db.response.affected_rows")
+				assertEqual(t, int64(1), affected.AsInt64())
+			})
+
+			t.Run("errors are recorded on the span", func(t *testing.T) {
+				db, recorder, _ := newTestDB(t, driverName)
+
+				_, err := db.ExecContext(ctx, "DELETE FROM fail")
+				assertTrue(t, errors.Is(err, errFakeQuery))
+
+				spans := recorder.Ended()
+				assertEqual(t, 1, len(spans))
+				assertEqual(t, codes.Error, spans[0].Status().Code)
+			})
+
+			t.Run("transaction span ends with outcome", func(t *testing.T) {
+				db, recorder, _ := newTestDB(t, driverName)
+
+				tx, err := db.BeginTx(ctx, nil)
+				assertNil(t, err)
+				_, err = tx.ExecContext(ctx, "INSERT INTO users (name) VALUES ('bob')")
+				assertNil(t, err)
+				assertNil(t, tx.Commit())
+
+				spans := recorder.Ended()
+				assertEqual(t, 2, len(spans))
+				assertEqual(t, "INSERT", spans[0].Name())
+				assertEqual(t, "db.transaction", spans[1].Name())
+				outcome, _ := spanAttr(spans[1], "db.transaction.outcome")
+				assertEqual(t, "commit", outcome.AsString())
+			})
+		})
+	}
+
+	t.Run("exports connection pool metrics", func(t *testing.T) {
+		_, _, reader := newTestDB(t, "volt-fake")
+
+		var rm metricdata.ResourceMetrics
+		assertNil(t, reader.Collect(ctx, &rm))
+
+		found := map[string]bool{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				found[m.Name] = true
+				if m.Name != "db.client.connections.open" {
+					continue
+				}
+				gauge := m.Data.(metricdata.Gauge[int64])
+				assertEqual(t, 1, len(gauge.DataPoints))
+				pool, _ := gauge.DataPoints[0].Attributes.Value("db.client.connections.pool.name")
+				assertEqual(t, "primary", pool.AsString())
+				assertEqual(t, int64(1), gauge.DataPoints[0].Value)
+			}
+		}
+
+		for _, name := range []string{
+			"db.client.connections.open",
+			"db.client.connections.in_use",
+			"db.client.connections.idle",
+			"db.client.connections.wait_count",
+			"db.client.connections.wait_duration",
+		} {
+			assertTrue(t, found[name])
+		}
+	})
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM t WHERE id = 1", "SELECT * FROM t WHERE id = ?"},
+		{"SELECT * FROM t WHERE name = 'o''brien'", "SELECT * FROM t WHERE name = ?"},
+		{"SELECT * FROM t2 WHERE id = $1", "SELECT * FROM t2 WHERE id = $1"},
+		{"SELECT  *\n\tFROM t WHERE x IN (1, 2.5, 0xFF)", "SELECT * FROM t WHERE x IN (?, ?, ?)"},
+		{"SELECT 1 -- secret\nFROM t /* token=abc */ WHERE a = :a", "SELECT ? FROM t WHERE a = :a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assertEqual(t, tt.want, sanitizeSQL(tt.query))
+		})
+	}
+}
+
+func TestDBSystemFromDriver(t *testing.T) {
+	tests := map[string]string{
+		"postgres": "postgresql",
+		"pgx":      "postgresql",
+		"mysql":    "mysql",
+		"sqlite3":  "sqlite",
+		"unknown":  "other_sql",
+	}
+
+	for driverName, want := range tests {
+		assertEqual(t, want, dbSystemFromDriver(driverName))
+	}
+}
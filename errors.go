@@ -2,13 +2,20 @@ package volt
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/danielgtaylor/huma/v2"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -21,6 +28,8 @@ type Error struct {
 	detail  string
 	cause   error
 	attrs   []attribute.KeyValue
+	params  map[string]any // for localized messages
+	stack   []uintptr      // where 5xx errors were created
 }
 
 // Error implements the error interface.
@@ -45,18 +54,24 @@ func (e *Error) GetStatus() int {
 
 // NewError creates a new Error with the given status and message.
 func NewError(status int, message string) *Error {
-	return &Error{
+	e := &Error{
 		status:  status,
 		message: message,
 	}
+	if status >= 500 {
+		e.stack = callers()
+	}
+	return e
 }
 
 // Wrap wraps an existing error with additional context.
 func Wrap(err error, message string) *Error {
 	return &Error{
 		status:  http.StatusInternalServerError,
+		code:    CodeInternal.Code,
 		message: message,
 		cause:   err,
+		stack:   callers(),
 	}
 }
 
@@ -66,8 +81,9 @@ func Wrap(err error, message string) *Error {
 func ErrNotFound(resource string) *Error {
 	return &Error{
 		status:  http.StatusNotFound,
-		code:    "NOT_FOUND",
+		code:    CodeNotFound.Code,
 		message: fmt.Sprintf("%s not found", resource),
+		params:  map[string]any{"resource": resource},
 	}
 }
 
@@ -75,7 +91,7 @@ func ErrNotFound(resource string) *Error {
 func ErrBadRequest(message string) *Error {
 	return &Error{
 		status:  http.StatusBadRequest,
-		code:    "BAD_REQUEST",
+		code:    CodeBadRequest.Code,
 		message: message,
 	}
 }
@@ -87,7 +103,7 @@ func ErrUnauthorized(message string) *Error {
 	}
 	return &Error{
 		status:  http.StatusUnauthorized,
-		code:    "UNAUTHORIZED",
+		code:    CodeUnauthorized.Code,
 		message: message,
 	}
 }
@@ -99,7 +115,7 @@ func ErrForbidden(message string) *Error {
 	}
 	return &Error{
 		status:  http.StatusForbidden,
-		code:    "FORBIDDEN",
+		code:    CodeForbidden.Code,
 		message: message,
 	}
 }
@@ -108,7 +124,7 @@ func ErrForbidden(message string) *Error {
 func ErrConflict(message string) *Error {
 	return &Error{
 		status:  http.StatusConflict,
-		code:    "CONFLICT",
+		code:    CodeConflict.Code,
 		message: message,
 	}
 }
@@ -117,7 +133,19 @@ func ErrConflict(message string) *Error {
 func ErrValidation(message string) *Error {
 	return &Error{
 		status:  http.StatusUnprocessableEntity,
-		code:    "VALIDATION_ERROR",
+		code:    CodeValidation.Code,
+		message: message,
+	}
+}
+
+// ErrTooManyRequests creates a 429 Too Many Requests error.
+func ErrTooManyRequests(message string) *Error {
+	if message == "" {
+		message = "rate limit exceeded"
+	}
+	return &Error{
+		status:  http.StatusTooManyRequests,
+		code:    CodeRateLimited.Code,
 		message: message,
 	}
 }
@@ -129,8 +157,9 @@ func ErrInternal(message string) *Error {
 	}
 	return &Error{
 		status:  http.StatusInternalServerError,
-		code:    "INTERNAL_ERROR",
+		code:    CodeInternal.Code,
 		message: message,
+		stack:   callers(),
 	}
 }
 
@@ -141,20 +170,97 @@ func ErrServiceUnavailable(message string) *Error {
 	}
 	return &Error{
 		status:  http.StatusServiceUnavailable,
-		code:    "SERVICE_UNAVAILABLE",
+		code:    CodeServiceUnavailable.Code,
 		message: message,
+		stack:   callers(),
 	}
 }
 
+// ErrorFromResponse decodes an error response from another service into an
+// *Error. It understands Huma's error model and problem+json bodies, and
+// falls back to the status text when the body can't be decoded. The body is
+// read but not closed.
+func ErrorFromResponse(resp *http.Response) *Error {
+	var body struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+		Code   string `json:"code"`
+		Errors []struct {
+			Message  string `json:"message"`
+			Location string `json:"location"`
+		} `json:"errors"`
+	}
+	if resp.Body != nil {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		_ = json.Unmarshal(data, &body)
+	}
+
+	message := body.Detail
+	if message == "" {
+		message = body.Title
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	e := NewError(resp.StatusCode, message).WithCode(body.Code)
+
+	details := make([]string, 0, len(body.Errors))
+	for _, d := range body.Errors {
+		if d.Location != "" {
+			details = append(details, d.Location+": "+d.Message)
+		} else {
+			details = append(details, d.Message)
+		}
+	}
+	return e.WithDetail(strings.Join(details, "; "))
+}
+
 // --- Error Methods ---
 
+// Code returns the error code, if any.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Detail returns the detailed error information, if any.
+func (e *Error) Detail() string {
+	return e.detail
+}
+
+// StackTrace returns where a 5xx error was created, formatted like a
+// goroutine trace, or "" for other errors.
+func (e *Error) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// callers captures the stack of the caller of an error constructor.
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
 // WithCode adds an error code.
 func (e *Error) WithCode(code string) *Error {
 	e.code = code
 	return e
 }
 
-// WithDetail adds detailed error information.
+// WithDetail adds detailed error information. In problem details it is the
+// message of the single entry in errors, while the error's message stays
+// the detail.
 func (e *Error) WithDetail(detail string) *Error {
 	e.detail = detail
 	return e
@@ -172,6 +278,19 @@ func (e *Error) WithAttr(key string, value string) *Error {
 	return e
 }
 
+// WithParam adds a parameter for the error's localized message template.
+//
+// Example:
+//
+//	return nil, ErrUserNotFound.New("user not found").WithParam("id", id)
+func (e *Error) WithParam(key string, value any) *Error {
+	if e.params == nil {
+		e.params = make(map[string]any)
+	}
+	e.params[key] = value
+	return e
+}
+
 // --- OTEL Integration ---
 
 // Record records the error on the current span.
@@ -186,13 +305,15 @@ func (e *Error) Record(ctx context.Context) *Error {
 		span.SetStatus(codes.Error, e.message)
 	}
 
-	// Record error
-	span.RecordError(e,
-		trace.WithAttributes(
-			attribute.Int("error.status", e.status),
-			attribute.String("error.code", e.code),
-		),
-	)
+	// Record error, with where it was created for 5xx errors
+	attrs := []attribute.KeyValue{
+		attribute.Int("error.status", e.status),
+		attribute.String("error.code", e.code),
+	}
+	if stack := e.StackTrace(); stack != "" {
+		attrs = append(attrs, semconv.ExceptionStacktrace(stack))
+	}
+	span.RecordError(e, trace.WithAttributes(attrs...))
 
 	// Add custom attributes
 	if len(e.attrs) > 0 {
@@ -202,25 +323,299 @@ func (e *Error) Record(ctx context.Context) *Error {
 	return e
 }
 
-// --- Huma Integration ---
+// --- Problem Details ---
+
+// ErrorModel is the RFC 9457 problem details body Volt writes for every
+// error response, as application/problem+json. It replaces Huma's default
+// error model, so validation and authorization errors use it too.
+//
+// The first call to New installs it by setting huma.NewError and
+// huma.NewErrorWithContext. Those are process-wide, so once an app exists,
+// other Huma APIs in the same process write ErrorModel too; importing the
+// package alone changes nothing.
+type ErrorModel struct {
+	Type     string              `json:"type,omitempty" format:"uri-reference" default:"about:blank" doc:"A URI reference identifying the problem type"`
+	Title    string              `json:"title,omitempty" example:"Not Found" doc:"A short, human-readable summary of the problem type"`
+	Status   int                 `json:"status,omitempty" example:"404" doc:"HTTP status code"`
+	Detail   string              `json:"detail,omitempty" example:"user not found" doc:"A human-readable explanation specific to this occurrence"`
+	Instance string              `json:"instance,omitempty" format:"uri-reference" example:"/users/123" doc:"A URI reference identifying this occurrence"`
+	Code     string              `json:"code,omitempty" example:"NOT_FOUND" doc:"Machine-readable error code"`
+	TraceID  string              `json:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736" doc:"Trace ID for debugging"`
+	Errors   []*huma.ErrorDetail `json:"errors,omitempty" doc:"Individual error details"`
+}
 
-// ToHumaError converts to a Huma error model.
+// Error implements the error interface.
+func (m *ErrorModel) Error() string {
+	return m.Detail
+}
+
+// GetStatus implements huma.StatusError.
+func (m *ErrorModel) GetStatus() int {
+	return m.Status
+}
+
+// ContentType implements huma.ContentTypeFilter.
+func (m *ErrorModel) ContentType(ct string) string {
+	if ct == "application/json" {
+		return "application/problem+json"
+	}
+	return ct
+}
+
+var installErrorModelOnce sync.Once
+
+// installErrorModel makes Huma build ErrorModel for its errors. New calls
+// it before creating the app's API, so the spec documents ErrorModel too.
+func installErrorModel() {
+	installErrorModelOnce.Do(func() {
+		huma.NewError = newErrorModel
+		huma.NewErrorWithContext = newErrorModelWithContext
+	})
+}
+
+// newErrorModelWithContext builds the problem details for Huma's errors
+// during a request.
+func newErrorModelWithContext(ctx huma.Context, status int, msg string, errs ...error) huma.StatusError {
+	// Problems already built by writeError are written as they are
+	var problem *ErrorModel
+	if msg == "" && len(errs) == 1 && errors.As(errs[0], &problem) {
+		return problem
+	}
+	model := newErrorModel(status, msg, errs...).(*ErrorModel)
+	model.Instance = ctx.URL().Path
+	model.TraceID = TraceID(ctx.Context())
+	linkErrorType(ctx.Context(), model)
+	localizeProblem(ctx.Context(), model)
+	return model
+}
+
+// newErrorModel builds the problem details for Huma's errors.
+func newErrorModel(status int, msg string, errs ...error) huma.StatusError {
+	model := &ErrorModel{
+		Status: status,
+		Title:  statusText(status),
+		Detail: msg,
+	}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var detailer huma.ErrorDetailer
+		if errors.As(err, &detailer) {
+			model.Errors = append(model.Errors, detailer.ErrorDetail())
+		} else {
+			model.Errors = append(model.Errors, &huma.ErrorDetail{Message: err.Error()})
+		}
+	}
+	return model
+}
+
+// ToHumaError converts the error to problem details and records it on the
+// current span. The message becomes detail, localized by code if the app
+// has a message bundle with a template whose placeholders the error's
+// params fill, and Detail becomes errors[0].message, which is how
+// ErrorFromResponse reads them back.
 func (e *Error) ToHumaError(ctx context.Context) huma.StatusError {
-	// Record on span
 	e.Record(ctx)
 
-	// Create Huma error detail
-	detail := &huma.ErrorDetail{
-		Message:  e.message,
-		Location: e.code,
+	title := statusText(e.status)
+	if code, ok := LookupErrorCode(e.code); ok && code.Status == e.status {
+		title = code.Title
+	}
+	model := &ErrorModel{
+		Status:   e.status,
+		Title:    title,
+		Detail:   e.message,
+		Instance: requestPath(ctx),
+		Code:     e.code,
+		TraceID:  TraceID(ctx),
 	}
+	if e.detail != "" {
+		model.Errors = []*huma.ErrorDetail{{Message: e.detail}}
+	}
+	linkErrorType(ctx, model)
+	if loc, ok := ctx.Value(localeKey).(locale); ok && e.code != "" {
+		if msg, ok := loc.bundle.errorMessage(loc.lang, e.code, e.params); ok {
+			model.Detail = msg
+		}
+	}
+	return model
+}
 
-	// Add trace ID if available
-	_ = TraceID(ctx)
+// problemFromError converts errors returned by handlers to problem details.
+// Other errors are left to Huma, which renders them through newErrorModel.
+func problemFromError(ctx context.Context, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.ToHumaError(ctx)
+	}
+
+	var model *ErrorModel
+	if errors.As(err, &model) {
+		if model.Instance == "" {
+			model.Instance = requestPath(ctx)
+		}
+		if model.TraceID == "" {
+			model.TraceID = TraceID(ctx)
+		}
+		linkErrorType(ctx, model)
+		localizeProblem(ctx, model)
+		recordProblem(ctx, model)
+		return model
+	}
+	return err
+}
+
+// writeError writes an error from Huma middleware as problem details, the
+// way errors returned by handlers are written.
+func (a *App) writeError(ctx huma.Context, err error) {
+	problem := problemFromError(ctx.Context(), err)
+	var model *ErrorModel
+	if !errors.As(problem, &model) {
+		_ = huma.WriteErr(a.api, ctx, StatusFromError(err), err.Error())
+		return
+	}
+	_ = huma.WriteErr(a.api, ctx, model.Status, "", model)
+}
+
+// linkErrorType links problems with a catalogued code to the code's entry
+// in the error catalog, if the app serves one.
+func linkErrorType(ctx context.Context, model *ErrorModel) {
+	catalog, _ := ctx.Value(errorCatalogKey).(string)
+	if catalog == "" || model.Type != "" || model.Code == "" {
+		return
+	}
+	if _, ok := LookupErrorCode(model.Code); ok {
+		model.Type = catalog + "#" + model.Code
+	}
+}
+
+// recordProblem records an error model on the current span.
+func recordProblem(ctx context.Context, model *ErrorModel) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	if model.Status >= 500 {
+		span.SetStatus(codes.Error, model.Detail)
+	}
+	span.RecordError(model, trace.WithAttributes(
+		attribute.Int("error.status", model.Status),
+		attribute.String("error.code", model.Code),
+	))
+}
+
+// statusText is http.StatusText with the de facto 499 status.
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
+
+// requestPathMiddleware makes the request path available to problemFromError
+// as the problem instance, along with the error catalog path.
+func (a *App) requestPathMiddleware(ctx huma.Context, next func(huma.Context)) {
+	ctx = huma.WithValue(ctx, requestPathKey, ctx.URL().Path)
+	if a.errorCatalogPath != "" {
+		ctx = huma.WithValue(ctx, errorCatalogKey, a.errorCatalogPath)
+	}
+	next(ctx)
+}
+
+// requestPath returns the path of the request being served, if any.
+func requestPath(ctx context.Context) string {
+	path, _ := ctx.Value(requestPathKey).(string)
+	return path
+}
+
+// --- Error Translation ---
+
+// StatusClientClosedRequest is the de facto status for requests the client
+// canceled before a response was written.
+const StatusClientClosedRequest = 499
+
+// ErrorTranslator maps an error returned by a handler to an *Error, or
+// returns nil to leave it to the next translator.
+//
+// Example:
+//
+//	func translateStripe(ctx context.Context, err error) *volt.Error {
+//	    var se *stripe.Error
+//	    if errors.As(err, &se) && se.Code == stripe.ErrorCodeCardDeclined {
+//	        return volt.NewError(402, "card declined").WithCode("CARD_DECLINED")
+//	    }
+//	    return nil
+//	}
+type ErrorTranslator func(ctx context.Context, err error) *Error
+
+// defaultErrorTranslators run after the app's own translators.
+var defaultErrorTranslators = []ErrorTranslator{
+	func(ctx context.Context, err error) *Error {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound("resource")
+		}
+		return nil
+	},
+	func(ctx context.Context, err error) *Error {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return CodeTimeout.New("request timed out")
+		}
+		return nil
+	},
+	func(ctx context.Context, err error) *Error {
+		if errors.Is(err, context.Canceled) {
+			return CodeClientClosedRequest.New("request canceled")
+		}
+		return nil
+	},
+}
+
+// translateError maps a handler error through the translator chain.
+// Errors that already carry a status are returned as is; errors no
+// translator handles become a 500 that doesn't expose their message.
+func (a *App) translateError(ctx context.Context, err error) error {
+	var se huma.StatusError
+	if errors.As(err, &se) {
+		return err
+	}
+	for _, translate := range a.errorTranslators {
+		if e := translate(ctx, err); e != nil {
+			if e.cause == nil {
+				e.cause = err
+			}
+			return e
+		}
+	}
+	return &Error{
+		status:  http.StatusInternalServerError,
+		code:    CodeInternal.Code,
+		message: "internal server error",
+		cause:   err,
+	}
+}
+
+// handleError translates an error returned by a handler of operation,
+// records it on the span, logs 5xx errors and returns the problem details.
+func (a *App) handleError(ctx *Context, operation string, err error) error {
+	translated := a.translateError(ctx, err)
+	status := StatusFromError(translated)
+
+	if status >= 500 {
+		attrs := []any{"operation", operation, "status", status, "error", err.Error()}
+		var e *Error
+		if errors.As(translated, &e) {
+			if e.code != "" {
+				attrs = append(attrs, "code", e.code)
+			}
+			if stack := e.StackTrace(); stack != "" {
+				attrs = append(attrs, "stack", stack)
+			}
+		}
+		Logger(ctx).ErrorContext(ctx, "request failed", attrs...)
+	}
 
-	return huma.NewError(e.status, e.message, detail).(*huma.ErrorModel)
-	// Note: You might want to customize this based on your needs
-	//_ = traceID // Use in custom error model if needed
+	return problemFromError(ctx, translated)
 }
 
 // --- Error Checking Helpers ---
@@ -1,9 +1,21 @@
 package volt
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestErrorConstructors(t *testing.T) {
@@ -49,6 +61,12 @@ func TestErrorConstructors(t *testing.T) {
 			expectedStatus: http.StatusUnprocessableEntity,
 			expectedCode:   "VALIDATION_ERROR",
 		},
+		{
+			name:           "ErrTooManyRequests",
+			constructor:    func() *Error { return ErrTooManyRequests("") },
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   "RATE_LIMITED",
+		},
 		{
 			name:           "ErrInternal",
 			constructor:    func() *Error { return ErrInternal("") },
@@ -204,3 +222,220 @@ func TestErrorsAs(t *testing.T) {
 		assertTrue(t, errors.As(outer, &voltErr))
 	})
 }
+
+func TestErrorFromResponse(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	t.Run("decodes huma error model", func(t *testing.T) {
+		err := ErrorFromResponse(response(422, `{"title":"Unprocessable Entity","detail":"validation failed","errors":[{"message":"expected string","location":"body.name"}]}`))
+
+		assertEqual(t, 422, err.GetStatus())
+		assertEqual(t, "validation failed", err.Error())
+		assertEqual(t, "body.name: expected string", err.Detail())
+		assertTrue(t, IsValidation(err))
+	})
+
+	t.Run("decodes code", func(t *testing.T) {
+		err := ErrorFromResponse(response(409, `{"title":"Conflict","code":"CONFLICT"}`))
+
+		assertEqual(t, "Conflict", err.Error())
+		assertEqual(t, "CONFLICT", err.Code())
+	})
+
+	t.Run("falls back to status text", func(t *testing.T) {
+		err := ErrorFromResponse(response(502, `<html>bad gateway</html>`))
+
+		assertEqual(t, 502, err.GetStatus())
+		assertEqual(t, "Bad Gateway", err.Error())
+	})
+}
+
+func TestInstallErrorModel(t *testing.T) {
+	newTestApp()
+	_, ok := huma.NewError(http.StatusBadRequest, "bad").(*ErrorModel)
+	assertTrue(t, ok)
+}
+
+func TestProblemDetails(t *testing.T) {
+	app, _ := newInMemoryApp(t)
+
+	type userInput struct {
+		ID    string `path:"id"`
+		Limit int    `query:"limit" maximum:"10"`
+	}
+	Register(app, Operation{Method: "GET", Path: "/users/{id}"}, func(ctx context.Context, in *userInput) (*struct{}, error) {
+		switch in.ID {
+		case "gone":
+			return nil, ErrNotFound("user").WithDetail("deleted on 2024-01-01")
+		case "taken":
+			return nil, huma.Error409Conflict("username taken")
+		}
+		return nil, nil
+	})
+	Register(app, WithAuthz(Operation{Method: "GET", Path: "/admin"}, AuthzPermission("admin")),
+		func(ctx context.Context, in *struct{}) (*struct{}, error) { return nil, nil })
+
+	get := func(path string) (*http.Response, ErrorModel) {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		var model ErrorModel
+		if err := json.Unmarshal(rec.Body.Bytes(), &model); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		return rec.Result(), model
+	}
+
+	t.Run("volt errors keep code and detail", func(t *testing.T) {
+		resp, model := get("/users/gone")
+		assertEqual(t, http.StatusNotFound, resp.StatusCode)
+		assertEqual(t, "application/problem+json", resp.Header.Get("Content-Type"))
+		assertEqual(t, "Not Found", model.Title)
+		assertEqual(t, 404, model.Status)
+		assertEqual(t, "user not found", model.Detail)
+		assertEqual(t, "/users/gone", model.Instance)
+		assertEqual(t, "NOT_FOUND", model.Code)
+		assertEqual(t, 32, len(model.TraceID))
+		assertEqual(t, "deleted on 2024-01-01", model.Errors[0].Message)
+	})
+
+	t.Run("round trips through ErrorFromResponse", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/users/gone", nil))
+		e := ErrorFromResponse(rec.Result())
+		assertEqual(t, "user not found", e.Error())
+		assertEqual(t, "NOT_FOUND", e.Code())
+		assertEqual(t, "deleted on 2024-01-01", e.Detail())
+	})
+
+	t.Run("huma errors", func(t *testing.T) {
+		resp, model := get("/users/taken")
+		assertEqual(t, http.StatusConflict, resp.StatusCode)
+		assertEqual(t, "username taken", model.Detail)
+		assertEqual(t, "/users/taken", model.Instance)
+		assertTrue(t, model.TraceID != "")
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		resp, model := get("/users/1?limit=50")
+		assertEqual(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assertEqual(t, "application/problem+json", resp.Header.Get("Content-Type"))
+		assertEqual(t, "/users/1", model.Instance)
+		assertTrue(t, model.TraceID != "")
+		assertEqual(t, "query.limit", model.Errors[0].Location)
+	})
+
+	t.Run("authorization errors", func(t *testing.T) {
+		resp, model := get("/admin")
+		assertEqual(t, http.StatusInternalServerError, resp.StatusCode)
+		assertEqual(t, "/admin", model.Instance)
+		assertTrue(t, model.TraceID != "")
+	})
+
+	t.Run("documented in OpenAPI", func(t *testing.T) {
+		oapi := app.API().OpenAPI()
+		schema := oapi.Components.Schemas.Map()["ErrorModel"]
+		assertNotNil(t, schema)
+		for _, field := range []string{"type", "title", "status", "detail", "instance", "code", "trace_id"} {
+			_, ok := schema.Properties[field]
+			assertTrue(t, ok)
+		}
+		response := oapi.Paths["/users/{id}"].Get.Responses["default"]
+		_, ok := response.Content["application/problem+json"]
+		assertTrue(t, ok)
+	})
+}
+
+func TestErrorTranslation(t *testing.T) {
+	errDeclined := errors.New("card declined by issuer")
+	app, mem := newInMemoryApp(t, WithErrorTranslators(func(ctx context.Context, err error) *Error {
+		if errors.Is(err, errDeclined) {
+			return NewError(http.StatusPaymentRequired, "payment declined").WithCode("PAYMENT_DECLINED")
+		}
+		return nil
+	}))
+
+	type failInput struct {
+		Kind string `path:"kind"`
+	}
+	Register(app, Operation{Method: "GET", Path: "/fail/{kind}", OperationID: "fail"}, func(ctx context.Context, in *failInput) (*struct{}, error) {
+		switch in.Kind {
+		case "no-rows":
+			return nil, fmt.Errorf("loading user: %w", sql.ErrNoRows)
+		case "deadline":
+			return nil, context.DeadlineExceeded
+		case "canceled":
+			return nil, context.Canceled
+		case "declined":
+			return nil, fmt.Errorf("charging: %w", errDeclined)
+		case "explicit":
+			return nil, ErrConflict("already exists").WithCause(sql.ErrNoRows)
+		case "internal":
+			return nil, ErrInternal("ledger out of balance")
+		}
+		return nil, errors.New("secret connection string leaked")
+	})
+
+	tests := []struct {
+		kind   string
+		status int
+		code   string
+		title  string
+	}{
+		{"no-rows", http.StatusNotFound, "NOT_FOUND", "Not Found"},
+		{"deadline", http.StatusGatewayTimeout, "TIMEOUT", "Gateway Timeout"},
+		{"canceled", StatusClientClosedRequest, "CLIENT_CLOSED_REQUEST", "Client Closed Request"},
+		{"declined", http.StatusPaymentRequired, "PAYMENT_DECLINED", "Payment Required"},
+		{"explicit", http.StatusConflict, "CONFLICT", "Conflict"},
+		{"internal", http.StatusInternalServerError, "INTERNAL_ERROR", "Internal Server Error"},
+		{"plain", http.StatusInternalServerError, "INTERNAL_ERROR", "Internal Server Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/fail/"+tt.kind, nil))
+			assertEqual(t, tt.status, rec.Code)
+
+			var model ErrorModel
+			assertNil(t, json.Unmarshal(rec.Body.Bytes(), &model))
+			assertEqual(t, tt.code, model.Code)
+			assertEqual(t, tt.title, model.Title)
+			assertTrue(t, !strings.Contains(rec.Body.String(), "secret"))
+		})
+	}
+
+	t.Run("errors are recorded on the span", func(t *testing.T) {
+		var span tracetest.SpanStub
+		var stack string
+		for _, s := range mem.Spans(SpanNamed("GET /fail/{kind}")) {
+			for _, event := range s.Events {
+				set := attribute.NewSet(event.Attributes...)
+				if code, _ := set.Value("error.code"); code.AsString() == "INTERNAL_ERROR" {
+					span = s
+					if v, ok := set.Value("exception.stacktrace"); ok {
+						stack = v.AsString()
+					}
+				}
+			}
+		}
+		assertEqual(t, codes.Error, span.Status.Code)
+		assertTrue(t, strings.Contains(stack, "TestErrorTranslation"))
+	})
+
+	t.Run("5xx errors are logged", func(t *testing.T) {
+		records := mem.Logs("request failed")
+		assertEqual(t, 3, len(records))
+		for _, r := range records {
+			assertEqual(t, "fail", r.Attr("operation"))
+			assertTrue(t, r.TraceID.IsValid())
+		}
+		var withStack int
+		for _, r := range records {
+			if strings.Contains(r.Attr("stack"), "TestErrorTranslation") {
+				withStack++
+			}
+		}
+		assertEqual(t, 1, withStack)
+	})
+}
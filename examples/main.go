@@ -290,6 +290,9 @@ func main() {
 		}),
 	)
 
+	// Kubernetes probes: /livez, /readyz, /startupz
+	volt.RegisterProbes(app)
+
 	// Users CRUD
 	volt.Register(app, volt.Operation{
 		Method:      "GET",
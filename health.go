@@ -0,0 +1,345 @@
+package volt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Health Check ---
+
+// HealthInput is empty for health checks.
+type HealthInput struct{}
+
+// HealthOutput represents health check response.
+type HealthOutput struct {
+	// 200 when healthy or degraded, 503 when a critical check fails
+	Status int
+
+	Body struct {
+		Status    string            `json:"status" example:"healthy" enum:"healthy,degraded,unhealthy,starting,draining"`
+		Version   string            `json:"version,omitempty"`
+		Checks    map[string]string `json:"checks,omitempty"`
+		Timestamp string            `json:"timestamp,omitempty"`
+	}
+}
+
+// RegisterHealthCheck adds a health check endpoint.
+//
+// Besides the given checks, every registered database is pinged and every
+// HTTP service with a HealthPath is probed. Checks run in parallel, each
+// with its own timeout, and results are cached for Config.Health.CacheTTL.
+//
+// The response is "healthy" when all checks pass and "degraded" when only
+// non-critical checks fail. If any critical check fails it is "unhealthy"
+// with status 503. Checkers that aren't a HealthCheck are non-critical, like
+// a HealthCheck without Critical set.
+func RegisterHealthCheck(app *App, path string, checks ...HealthChecker) {
+	if path == "" {
+		path = "/health"
+	}
+
+	runner := newHealthRunner(app.config.Health)
+
+	Register(app, Operation{
+		Method:  "GET",
+		Path:    path,
+		Summary: "Health check",
+		Tags:    []string{"health"},
+	}, func(ctx context.Context, input *HealthInput) (*HealthOutput, error) {
+		all := make([]healthCheck, 0, len(checks))
+		for i, check := range checks {
+			all = append(all, newHealthCheck(fmt.Sprintf("check:%d", i), check))
+		}
+		for _, check := range app.registry.HealthChecks() {
+			all = append(all, newHealthCheck("service:"+check.Name, check))
+		}
+
+		return runner.report(ctx, all, app.config.Version), nil
+	})
+}
+
+// HealthChecker interface for custom health checks.
+type HealthChecker interface {
+	Check(ctx context.Context) (name string, status string)
+}
+
+// HealthCheckFunc is a function adapter for HealthChecker.
+type HealthCheckFunc func(ctx context.Context) (name string, status string)
+
+func (f HealthCheckFunc) Check(ctx context.Context) (string, string) {
+	return f(ctx)
+}
+
+// HealthCheck is a HealthChecker with execution options.
+//
+// Example:
+//
+//	volt.RegisterHealthCheck(app, "/health", volt.HealthCheck{
+//	    Name:     "cache",
+//	    Timeout:  500 * time.Millisecond,
+//	    Critical: false,
+//	    Func: func(ctx context.Context) error {
+//	        return cache.Ping(ctx)
+//	    },
+//	})
+type HealthCheck struct {
+	Name string
+	Func func(ctx context.Context) error
+
+	// Per-check timeout (default: Config.Health.Timeout)
+	Timeout time.Duration
+
+	// A failing critical check makes the endpoint return 503;
+	// other failures only report the service as degraded.
+	Critical bool
+
+	// Probes this check participates in (default: readiness only).
+	// The /health endpoint runs every check regardless.
+	Probes []Probe
+}
+
+// Check implements HealthChecker. The status is "ok" or the error message.
+func (c HealthCheck) Check(ctx context.Context) (string, string) {
+	if err := c.Func(ctx); err != nil {
+		return c.Name, err.Error()
+	}
+	return c.Name, "ok"
+}
+
+// HealthChecks returns checks for every registered database and every HTTP
+// service configured with a HealthPath or circuit breaker, sorted by name.
+func (r *Registry) HealthChecks() []HealthCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var checks []HealthCheck
+
+	for name, factory := range r.dbServices {
+		if _, ok := r.overrides[name]; ok {
+			continue
+		}
+		checks = append(checks, HealthCheck{
+			Name:     name,
+			Timeout:  factory.config.HealthTimeout,
+			Critical: !factory.config.HealthOptional,
+			Func: func(ctx context.Context) error {
+				r.mu.RLock()
+				db := factory.db
+				r.mu.RUnlock()
+
+				if db == nil {
+					return fmt.Errorf("database not initialized")
+				}
+				return db.PingContext(ctx)
+			},
+		})
+	}
+
+	for name, factory := range r.httpServices {
+		if _, ok := r.overrides[name]; ok {
+			continue
+		}
+		if factory.config.HealthPath == "" && factory.config.CircuitBreaker == nil {
+			continue
+		}
+		url := ""
+		if factory.config.HealthPath != "" {
+			url = strings.TrimSuffix(factory.baseURL, "/") + "/" + strings.TrimPrefix(factory.config.HealthPath, "/")
+		}
+		checks = append(checks, HealthCheck{
+			Name:     name,
+			Timeout:  factory.config.HealthTimeout,
+			Critical: factory.config.HealthCritical,
+			Func: func(ctx context.Context) error {
+				r.mu.RLock()
+				client, breaker := factory.httpClient, factory.breaker
+				r.mu.RUnlock()
+
+				if client == nil {
+					return fmt.Errorf("HTTP service not initialized")
+				}
+				if breaker != nil && breaker.State() == CircuitOpen {
+					return fmt.Errorf("circuit breaker open")
+				}
+				if url == "" {
+					return nil
+				}
+				return probeHTTP(ctx, client, url)
+			},
+		})
+	}
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks
+}
+
+// probeHTTP sends a GET request and expects a 2xx response.
+func probeHTTP(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// --- Check Execution ---
+
+// healthCheck is a normalized check. key identifies it in the cache.
+type healthCheck struct {
+	key      string
+	checker  HealthChecker
+	timeout  time.Duration
+	critical bool
+}
+
+func newHealthCheck(key string, checker HealthChecker) healthCheck {
+	check := healthCheck{key: key, checker: checker}
+	if hc, ok := checker.(HealthCheck); ok {
+		check.timeout = hc.Timeout
+		check.critical = hc.Critical
+	}
+	return check
+}
+
+type healthResult struct {
+	name     string
+	status   string
+	critical bool
+	at       time.Time
+}
+
+func (r healthResult) healthy() bool {
+	return r.status == "ok" || r.status == "healthy"
+}
+
+// healthRunner runs checks in parallel and caches their results.
+type healthRunner struct {
+	config HealthConfig
+
+	mu    sync.Mutex
+	cache map[string]healthResult
+}
+
+func newHealthRunner(config HealthConfig) *healthRunner {
+	return &healthRunner{config: config, cache: make(map[string]healthResult)}
+}
+
+// report runs checks and builds the endpoint response.
+func (h *healthRunner) report(ctx context.Context, checks []healthCheck, version string) *HealthOutput {
+	out := &HealthOutput{Status: http.StatusOK}
+	out.Body.Status = "healthy"
+	out.Body.Version = version
+	out.Body.Checks = make(map[string]string)
+	out.Body.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	for _, result := range h.run(ctx, checks) {
+		out.Body.Checks[result.name] = result.status
+		if result.healthy() {
+			continue
+		}
+		if result.critical {
+			out.Status = http.StatusServiceUnavailable
+			out.Body.Status = "unhealthy"
+		} else if out.Body.Status == "healthy" {
+			out.Body.Status = "degraded"
+		}
+	}
+
+	return out
+}
+
+// run returns one result per check, in order. Cached results younger than
+// CacheTTL are reused; all other checks run concurrently.
+func (h *healthRunner) run(ctx context.Context, checks []healthCheck) []healthResult {
+	results := make([]healthResult, len(checks))
+	fresh := make([]bool, len(checks))
+	now := time.Now()
+
+	// Results are shared between requests, so one client going away must
+	// not cancel (and cache the failure of) the checks it started
+	ctx = context.WithoutCancel(ctx)
+
+	var wg sync.WaitGroup
+	h.mu.Lock()
+	for i, check := range checks {
+		if cached, ok := h.cache[check.key]; ok && now.Sub(cached.at) < h.config.CacheTTL {
+			results[i] = cached
+			continue
+		}
+
+		fresh[i] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.runOne(ctx, check)
+		}()
+	}
+	h.mu.Unlock()
+	wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, check := range checks {
+		if fresh[i] {
+			h.cache[check.key] = results[i]
+		}
+	}
+
+	return results
+}
+
+// runOne runs a single check with its timeout. A check that ignores its
+// context is abandoned once the timeout expires.
+func (h *healthRunner) runOne(ctx context.Context, check healthCheck) healthResult {
+	timeout := check.timeout
+	if timeout <= 0 {
+		timeout = h.config.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct{ name, status string }
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- outcome{status: fmt.Sprintf("panic: %v", p)}
+			}
+		}()
+		name, status := check.checker.Check(ctx)
+		done <- outcome{name, status}
+	}()
+
+	result := healthResult{critical: check.critical}
+	select {
+	case o := <-done:
+		result.name, result.status = o.name, o.status
+	case <-ctx.Done():
+		result.status = "timeout: " + ctx.Err().Error()
+	}
+	if result.name == "" {
+		result.name = check.key
+		if hc, ok := check.checker.(HealthCheck); ok && hc.Name != "" {
+			result.name = hc.Name
+		}
+	}
+	result.at = time.Now()
+	return result
+}
@@ -0,0 +1,212 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckFunc(t *testing.T) {
+	t.Run("adapts function to HealthChecker interface", func(t *testing.T) {
+		checker := HealthCheckFunc(func(ctx context.Context) (string, string) {
+			return "database", "ok"
+		})
+
+		name, status := checker.Check(context.Background())
+
+		assertEqual(t, "database", name)
+		assertEqual(t, "ok", status)
+	})
+
+	t.Run("can return unhealthy status", func(t *testing.T) {
+		checker := HealthCheckFunc(func(ctx context.Context) (string, string) {
+			return "redis", "connection failed"
+		})
+
+		name, status := checker.Check(context.Background())
+
+		assertEqual(t, "redis", name)
+		assertEqual(t, "connection failed", status)
+	})
+}
+
+func TestRegisterHealthCheck(t *testing.T) {
+	type response struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+
+	get := func(t *testing.T, app *App) (int, response) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+		var body response
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec.Code, body
+	}
+
+	failing := func(name string, critical bool) HealthCheck {
+		return HealthCheck{Name: name, Critical: critical, Func: func(ctx context.Context) error {
+			return errors.New("down")
+		}}
+	}
+
+	t.Run("healthy when all checks pass", func(t *testing.T) {
+		app := newTestApp()
+		RegisterHealthCheck(app, "", HealthCheckFunc(func(ctx context.Context) (string, string) {
+			return "custom", "ok"
+		}))
+
+		code, body := get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "healthy", body.Status)
+		assertEqual(t, "ok", body.Checks["custom"])
+	})
+
+	t.Run("degraded when a non-critical check fails", func(t *testing.T) {
+		app := newTestApp()
+		RegisterHealthCheck(app, "", failing("cache", false))
+
+		code, body := get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "degraded", body.Status)
+		assertEqual(t, "down", body.Checks["cache"])
+	})
+
+	t.Run("503 when a critical check fails", func(t *testing.T) {
+		app := newTestApp()
+		RegisterHealthCheck(app, "", failing("cache", false), failing("queue", true))
+
+		code, body := get(t, app)
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		assertEqual(t, "unhealthy", body.Status)
+	})
+
+	t.Run("plain checkers are non-critical", func(t *testing.T) {
+		app := newTestApp()
+		RegisterHealthCheck(app, "", HealthCheckFunc(func(ctx context.Context) (string, string) {
+			return "legacy", "connection refused"
+		}))
+
+		code, body := get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "degraded", body.Status)
+	})
+
+	t.Run("checks time out individually", func(t *testing.T) {
+		app := newTestApp(WithHealthCheckTimeout(time.Second))
+		RegisterHealthCheck(app, "", HealthCheck{
+			Name:     "slow",
+			Timeout:  10 * time.Millisecond,
+			Critical: true,
+			Func: func(ctx context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		})
+
+		start := time.Now()
+		code, body := get(t, app)
+		assertTrue(t, time.Since(start) < 500*time.Millisecond)
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		assertEqual(t, "timeout: context deadline exceeded", body.Checks["slow"])
+	})
+
+	t.Run("checks run in parallel", func(t *testing.T) {
+		app := newTestApp()
+		var checks []HealthChecker
+		for _, name := range []string{"a", "b", "c", "d"} {
+			checks = append(checks, HealthCheck{Name: name, Func: func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			}})
+		}
+		RegisterHealthCheck(app, "", checks...)
+
+		start := time.Now()
+		get(t, app)
+		assertTrue(t, time.Since(start) < 150*time.Millisecond)
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		var calls atomic.Int32
+		check := HealthCheck{Name: "counted", Func: func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		}}
+
+		app := newTestApp(WithHealthCacheTTL(time.Minute))
+		RegisterHealthCheck(app, "", check)
+		get(t, app)
+		get(t, app)
+		assertEqual(t, int32(1), calls.Load())
+
+		calls.Store(0)
+		app = newTestApp(WithHealthCacheTTL(-1))
+		RegisterHealthCheck(app, "", check)
+		get(t, app)
+		get(t, app)
+		assertEqual(t, int32(2), calls.Load())
+	})
+
+	t.Run("includes registered databases", func(t *testing.T) {
+		app := newTestApp(WithHealthCacheTTL(-1))
+		config := DefaultDatabaseConfig()
+		config.Driver = "volt-fake"
+		RegisterDatabase(app, "primary", config)
+		RegisterHealthCheck(app, "")
+
+		// Not yet initialized
+		code, body := get(t, app)
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		assertEqual(t, "database not initialized", body.Checks["primary"])
+
+		ctx := context.Background()
+		assertNil(t, app.registry.Initialize(ctx, app))
+		defer app.registry.Shutdown(ctx)
+
+		code, body = get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "ok", body.Checks["primary"])
+	})
+
+	t.Run("probes HTTP services with a health path", func(t *testing.T) {
+		var status atomic.Int32
+		status.Store(http.StatusOK)
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assertEqual(t, "/healthz", r.URL.Path)
+			w.WriteHeader(int(status.Load()))
+		}))
+		defer upstream.Close()
+
+		app := newTestApp(WithHealthCacheTTL(-1))
+		RegisterHTTPService(app, "billing", func(c *http.Client) *http.Client { return c },
+			WithHTTPBaseURL(upstream.URL),
+			WithHTTPRetries(0, 0, 0),
+			WithHTTPHealthCheck("/healthz", false),
+		)
+		RegisterHTTPService(app, "unprobed", func(c *http.Client) *http.Client { return c })
+		RegisterHealthCheck(app, "")
+		assertNil(t, app.registry.Initialize(context.Background(), app))
+
+		code, body := get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "ok", body.Checks["billing"])
+		_, probed := body.Checks["unprobed"]
+		assertTrue(t, !probed)
+
+		status.Store(http.StatusInternalServerError)
+		code, body = get(t, app)
+		assertEqual(t, http.StatusOK, code)
+		assertEqual(t, "degraded", body.Status)
+		assertEqual(t, "unexpected status 500", body.Checks["billing"])
+	})
+}
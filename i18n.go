@@ -0,0 +1,322 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/danielgtaylor/huma/v2"
+	"golang.org/x/text/language"
+)
+
+// --- Localized Messages ---
+
+// MessageBundle holds message templates per language. Templates for Volt
+// errors are keyed by error code and may reference the error's parameters
+// as {name}. Huma's messages are keyed by their English text, e.g.
+// "validation failed" or "expected number <= %v"; format verbs in those
+// translations are replaced by the values of the original message, in order.
+//
+// Example:
+//
+//	messages := volt.NewMessageBundle("en")
+//	messages.Add("de", map[string]string{
+//	    "NOT_FOUND":             "{resource} nicht gefunden",
+//	    "validation failed":     "Validierung fehlgeschlagen",
+//	    "expected number <= %v": "Zahl <= %v erwartet",
+//	})
+//	app := volt.New(volt.WithMessageBundle(messages))
+type MessageBundle struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	tags     []language.Tag // fallback first
+	matcher  language.Matcher
+	messages map[language.Tag]map[string]string
+	formats  []messageFormat // keys with format verbs, longest first
+	decoders map[string]func(data []byte, v any) error
+}
+
+// NewMessageBundle creates a bundle that falls back to the given language
+// when none of the client's languages is available.
+func NewMessageBundle(fallback string) *MessageBundle {
+	tag := language.Make(fallback)
+	return &MessageBundle{
+		fallback: tag,
+		tags:     []language.Tag{tag},
+		matcher:  language.NewMatcher([]language.Tag{tag}),
+		messages: map[language.Tag]map[string]string{},
+		decoders: map[string]func([]byte, any) error{".json": json.Unmarshal},
+	}
+}
+
+// RegisterFormat adds a decoder for message files with the given extension,
+// e.g. RegisterFormat(".toml", toml.Unmarshal). JSON is supported by default.
+func (b *MessageBundle) RegisterFormat(ext string, decode func(data []byte, v any) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.decoders[ext] = decode
+}
+
+// Add adds message templates for a language, replacing existing ones with
+// the same key.
+func (b *MessageBundle) Add(lang string, messages map[string]string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("invalid language %q: %w", lang, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[tag] == nil {
+		b.messages[tag] = map[string]string{}
+		if tag != b.fallback {
+			b.tags = append(b.tags, tag)
+			b.matcher = language.NewMatcher(b.tags)
+		}
+	}
+	for key, msg := range messages {
+		b.messages[tag][key] = msg
+		if formatVerb.MatchString(key) && !slices.ContainsFunc(b.formats, func(f messageFormat) bool { return f.key == key }) {
+			b.formats = append(b.formats, messageFormat{key: key, pattern: formatPattern(key)})
+		}
+	}
+	slices.SortStableFunc(b.formats, func(x, y messageFormat) int { return len(y.key) - len(x.key) })
+	return nil
+}
+
+// LoadFS adds the message files in fsys matching pattern. Each file holds
+// one language, named after the file, e.g. "locales/de.json" or
+// "locales/pt-BR.toml", and contains a flat object of templates.
+//
+// Example:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	messages := volt.NewMessageBundle("en")
+//	messages.RegisterFormat(".toml", toml.Unmarshal)
+//	if err := messages.LoadFS(locales, "locales/*"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (b *MessageBundle) LoadFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		ext := path.Ext(file)
+		b.mu.RLock()
+		decode, ok := b.decoders[ext]
+		b.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("loading %s: no decoder for %q files", file, ext)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := decode(data, &messages); err != nil {
+			return fmt.Errorf("loading %s: %w", file, err)
+		}
+		if err := b.Add(strings.TrimSuffix(path.Base(file), ext), messages); err != nil {
+			return fmt.Errorf("loading %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// Languages returns the languages of the bundle, fallback first.
+func (b *MessageBundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, len(b.tags))
+	for i, tag := range b.tags {
+		langs[i] = tag.String()
+	}
+	return langs
+}
+
+// Match returns the best available language for an Accept-Language header.
+func (b *MessageBundle) Match(acceptLanguage string) string {
+	prefs, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, index, confidence := b.matcher.Match(prefs...)
+	if confidence == language.No {
+		return b.fallback.String()
+	}
+	return b.tags[index].String()
+}
+
+// Message renders the template for key in lang, falling back to the
+// bundle's fallback language.
+func (b *MessageBundle) Message(lang, key string, params map[string]any) (string, bool) {
+	tmpl, ok := b.template(language.Make(lang), key)
+	if !ok {
+		return "", false
+	}
+	return expandParams(tmpl, params), true
+}
+
+// errorMessage renders the template for an error code like Message, but
+// only if params fill all of its placeholders, so errors without them keep
+// their own message.
+func (b *MessageBundle) errorMessage(lang, code string, params map[string]any) (string, bool) {
+	tmpl, ok := b.template(language.Make(lang), code)
+	if !ok {
+		return "", false
+	}
+	for _, match := range placeholder.FindAllStringSubmatch(tmpl, -1) {
+		if _, ok := params[match[1]]; !ok {
+			return "", false
+		}
+	}
+	return expandParams(tmpl, params), true
+}
+
+// translate translates one of Huma's English messages to lang, matching
+// messages formatted from keys with format verbs.
+func (b *MessageBundle) translate(lang, msg string) (string, bool) {
+	tag := language.Make(lang)
+	if tmpl, ok := b.template(tag, msg); ok {
+		return tmpl, true
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, format := range b.formats {
+		args := format.pattern.FindStringSubmatch(msg)
+		if args == nil {
+			continue
+		}
+		tmpl, ok := b.lookup(tag, format.key)
+		if !ok {
+			continue
+		}
+		args = args[1:]
+		return formatVerb.ReplaceAllStringFunc(tmpl, func(verb string) string {
+			if len(args) == 0 {
+				return verb
+			}
+			arg := args[0]
+			args = args[1:]
+			return arg
+		}), true
+	}
+	return "", false
+}
+
+func (b *MessageBundle) template(tag language.Tag, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lookup(tag, key)
+}
+
+func (b *MessageBundle) lookup(tag language.Tag, key string) (string, bool) {
+	if tmpl, ok := b.messages[tag][key]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := b.messages[b.fallback][key]
+	return tmpl, ok
+}
+
+// messageFormat is a key with format verbs and the pattern matching
+// messages formatted from it.
+type messageFormat struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+var (
+	formatVerb  = regexp.MustCompile(`%[vdsq]`)
+	placeholder = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// formatPattern compiles a format string to a pattern capturing its
+// arguments.
+func formatPattern(format string) *regexp.Regexp {
+	parts := formatVerb.Split(format, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$")
+}
+
+// expandParams replaces {name} placeholders with the given parameters,
+// leaving unknown ones as is.
+func expandParams(tmpl string, params map[string]any) string {
+	return placeholder.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if value, ok := params[match[1:len(match)-1]]; ok {
+			return fmt.Sprint(value)
+		}
+		return match
+	})
+}
+
+// --- Language Negotiation ---
+
+// locale is the negotiated language of a request.
+type locale struct {
+	bundle *MessageBundle
+	lang   string
+}
+
+// localeMiddleware negotiates the response language from Accept-Language
+// and sets Content-Language.
+func (a *App) localeMiddleware(ctx huma.Context, next func(huma.Context)) {
+	lang := a.config.Messages.Match(ctx.Header("Accept-Language"))
+	ctx.AppendHeader("Vary", "Accept-Language")
+	ctx.SetHeader("Content-Language", lang)
+	next(huma.WithValue(ctx, localeKey, locale{bundle: a.config.Messages, lang: lang}))
+}
+
+// Language returns the language negotiated for the request, or "" if the
+// app has no message bundle.
+func Language(ctx context.Context) string {
+	loc, _ := ctx.Value(localeKey).(locale)
+	return loc.lang
+}
+
+// Localize renders the message template for key in the request's language,
+// returning key itself if there is no template.
+//
+// Example:
+//
+//	greeting := volt.Localize(ctx, "welcome", map[string]any{"name": user.Name})
+func Localize(ctx context.Context, key string, params map[string]any) string {
+	loc, ok := ctx.Value(localeKey).(locale)
+	if !ok {
+		return key
+	}
+	msg, ok := loc.bundle.Message(loc.lang, key, params)
+	if !ok {
+		return key
+	}
+	return msg
+}
+
+// localizeProblem translates the details of Huma's problems to the
+// request's language.
+func localizeProblem(ctx context.Context, model *ErrorModel) {
+	loc, ok := ctx.Value(localeKey).(locale)
+	if !ok {
+		return
+	}
+	if msg, ok := loc.bundle.translate(loc.lang, model.Detail); ok {
+		model.Detail = msg
+	}
+	for _, detail := range model.Errors {
+		if msg, ok := loc.bundle.translate(loc.lang, detail.Message); ok {
+			detail.Message = msg
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package volt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMessageBundle(t *testing.T) {
+	messages := NewMessageBundle("en")
+	assertNil(t, messages.Add("en", map[string]string{"NOT_FOUND": "{resource} not found"}))
+	assertNil(t, messages.Add("de", map[string]string{
+		"NOT_FOUND":                "{resource} nicht gefunden",
+		"expected number <= %v":    "Zahl <= %v erwartet",
+		"expected string to be %s": "Zeichenkette erwartet: %s",
+		"expected property %s to be present when %s is present": "Eigenschaft %s fehlt, obwohl %s gesetzt ist",
+	}))
+
+	t.Run("negotiation", func(t *testing.T) {
+		assertEqual(t, "de", messages.Match("de-CH, en;q=0.5"))
+		assertEqual(t, "en", messages.Match("fr, en;q=0.8, de;q=0.5"))
+		assertEqual(t, "en", messages.Match("ja"))
+		assertEqual(t, "en", messages.Match(""))
+		assertEqual(t, "en,de", strings.Join(messages.Languages(), ","))
+	})
+
+	t.Run("templates with parameters", func(t *testing.T) {
+		msg, ok := messages.Message("de", "NOT_FOUND", map[string]any{"resource": "Benutzer"})
+		assertTrue(t, ok)
+		assertEqual(t, "Benutzer nicht gefunden", msg)
+
+		msg, _ = messages.Message("de", "NOT_FOUND", nil)
+		assertEqual(t, "{resource} nicht gefunden", msg)
+
+		_, ok = messages.Message("de", "CONFLICT", nil)
+		assertTrue(t, !ok)
+	})
+
+	t.Run("falls back to the fallback language", func(t *testing.T) {
+		msg, ok := messages.Message("fr", "NOT_FOUND", map[string]any{"resource": "user"})
+		assertTrue(t, ok)
+		assertEqual(t, "user not found", msg)
+	})
+
+	t.Run("formatted messages", func(t *testing.T) {
+		msg, ok := messages.translate("de", "expected number <= 10")
+		assertTrue(t, ok)
+		assertEqual(t, "Zahl <= 10 erwartet", msg)
+
+		msg, _ = messages.translate("de", "expected property b to be present when a is present")
+		assertEqual(t, "Eigenschaft b fehlt, obwohl a gesetzt ist", msg)
+
+		_, ok = messages.translate("de", "expected number >= 10")
+		assertTrue(t, !ok)
+		_, ok = messages.translate("en", "expected number <= 10")
+		assertTrue(t, !ok)
+	})
+
+	t.Run("rejects invalid languages", func(t *testing.T) {
+		assertTrue(t, messages.Add("not a language", nil) != nil)
+	})
+}
+
+func TestMessageBundleLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.json":  {Data: []byte(`{"NOT_FOUND": "{resource} nicht gefunden"}`)},
+		"locales/fr.toml":  {Data: []byte("NOT_FOUND = \"{resource} introuvable\"\n")},
+		"locales/README":   {Data: []byte("translations")},
+		"locales/es.json5": {Data: []byte("{}")},
+	}
+
+	messages := NewMessageBundle("en")
+	messages.RegisterFormat(".toml", decodeFlatTOML)
+	assertNil(t, messages.LoadFS(fsys, "locales/*.json"))
+	assertNil(t, messages.LoadFS(fsys, "locales/*.toml"))
+
+	msg, _ := messages.Message("fr", "NOT_FOUND", map[string]any{"resource": "utilisateur"})
+	assertEqual(t, "utilisateur introuvable", msg)
+	msg, _ = messages.Message("de", "NOT_FOUND", map[string]any{"resource": "Benutzer"})
+	assertEqual(t, "Benutzer nicht gefunden", msg)
+
+	err := messages.LoadFS(fsys, "locales/es.*")
+	assertTrue(t, err != nil && strings.Contains(err.Error(), `no decoder for ".json5" files`))
+
+	fsys["locales/it.json"] = &fstest.MapFile{Data: []byte(`{"NOT_FOUND": 1}`)}
+	err = messages.LoadFS(fsys, "locales/it.json")
+	assertTrue(t, err != nil && strings.Contains(err.Error(), "loading locales/it.json"))
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	messages := NewMessageBundle("en")
+	assertNil(t, messages.Add("de", map[string]string{
+		"NOT_FOUND":             "{resource} nicht gefunden",
+		"TEST_ORDER_LIMIT":      "Höchstens {max} Bestellungen erlaubt",
+		"validation failed":     "Validierung fehlgeschlagen",
+		"expected number <= %v": "Zahl <= %v erwartet",
+		"welcome":               "Willkommen, {name}",
+	}))
+	app := newTestApp(WithMessageBundle(messages))
+
+	type orderInput struct {
+		ID    string `path:"id"`
+		Limit int    `query:"limit" maximum:"10"`
+	}
+	var language, greeting string
+	Register(app, Operation{Method: "GET", Path: "/orders/{id}"}, func(ctx context.Context, in *orderInput) (*struct{}, error) {
+		language = Language(ctx)
+		greeting = Localize(ctx, "welcome", map[string]any{"name": "Ada"})
+		switch in.ID {
+		case "many":
+			return nil, NewError(http.StatusConflict, "at most 5 orders").WithCode("TEST_ORDER_LIMIT").WithParam("max", 5)
+		case "other":
+			return nil, ErrConflict("order is locked")
+		case "archived":
+			return nil, CodeNotFound.Newf("order %s is archived", in.ID)
+		}
+		return nil, ErrNotFound("Bestellung")
+	})
+
+	get := func(path, acceptLanguage string) (*httptest.ResponseRecorder, ErrorModel) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		app.Router().ServeHTTP(rec, req)
+		var model ErrorModel
+		if err := json.Unmarshal(rec.Body.Bytes(), &model); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		return rec, model
+	}
+
+	t.Run("error templates keyed by code", func(t *testing.T) {
+		rec, model := get("/orders/1", "de-DE,de;q=0.9,en;q=0.5")
+		assertEqual(t, "de", rec.Header().Get("Content-Language"))
+		assertEqual(t, "Bestellung nicht gefunden", model.Detail)
+		assertEqual(t, "NOT_FOUND", model.Code)
+		assertEqual(t, "de", language)
+		assertEqual(t, "Willkommen, Ada", greeting)
+
+		_, model = get("/orders/many", "de")
+		assertEqual(t, "Höchstens 5 Bestellungen erlaubt", model.Detail)
+	})
+
+	t.Run("untranslated errors keep their message", func(t *testing.T) {
+		_, model := get("/orders/other", "de")
+		assertEqual(t, "order is locked", model.Detail)
+	})
+
+	t.Run("templates with unfilled placeholders keep the message", func(t *testing.T) {
+		rec, model := get("/orders/archived", "de")
+		assertEqual(t, "de", rec.Header().Get("Content-Language"))
+		assertEqual(t, "order archived is archived", model.Detail)
+		assertEqual(t, "NOT_FOUND", model.Code)
+	})
+
+	t.Run("fallback language", func(t *testing.T) {
+		rec, model := get("/orders/1", "fr")
+		assertEqual(t, "en", rec.Header().Get("Content-Language"))
+		assertEqual(t, "Bestellung not found", model.Detail)
+		assertEqual(t, "en", language)
+		assertEqual(t, "welcome", greeting)
+	})
+
+	t.Run("validation messages", func(t *testing.T) {
+		rec, model := get("/orders/1?limit=50", "de")
+		assertEqual(t, http.StatusUnprocessableEntity, rec.Code)
+		assertEqual(t, "Validierung fehlgeschlagen", model.Detail)
+		assertEqual(t, "Zahl <= 10 erwartet", model.Errors[0].Message)
+
+		_, model = get("/orders/1?limit=50", "en")
+		assertEqual(t, "expected number <= 10", model.Errors[0].Message)
+	})
+
+	t.Run("without a bundle", func(t *testing.T) {
+		plain := newTestApp()
+		Register(plain, Operation{Method: "GET", Path: "/orders/{id}"}, func(ctx context.Context, in *orderInput) (*struct{}, error) {
+			language = Language(ctx)
+			greeting = Localize(ctx, "welcome", nil)
+			return nil, ErrNotFound("order")
+		})
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/orders/1", nil)
+		req.Header.Set("Accept-Language", "de")
+		plain.Router().ServeHTTP(rec, req)
+
+		assertEqual(t, "", rec.Header().Get("Content-Language"))
+		assertTrue(t, strings.Contains(rec.Body.String(), `"detail":"order not found"`))
+		assertEqual(t, "", language)
+		assertEqual(t, "welcome", greeting)
+	})
+}
+
+// decodeFlatTOML decodes TOML files of unquoted keys and quoted string
+// values, enough to exercise RegisterFormat without a TOML dependency.
+func decodeFlatTOML(data []byte, v any) error {
+	messages := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		unquoted, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		messages[strings.TrimSpace(key)] = unquoted
+	}
+	*(v.(*map[string]string)) = messages
+	return scanner.Err()
+}
@@ -0,0 +1,178 @@
+package volt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// --- Outbound Rate Limiting ---
+
+// WithHTTPRateLimit caps outbound requests to the service at rps requests
+// per second, allowing bursts of up to burst requests (default: rps rounded
+// up). Requests over the budget wait until a token is available or their
+// context is done.
+func WithHTTPRateLimit(rps float64, burst int) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.RequestsPerSecond = rps
+		c.Burst = burst
+	}
+}
+
+// WithHTTPMaxInFlight caps concurrent requests to the service. A request
+// holds its slot until the response body is closed.
+func WithHTTPMaxInFlight(n int) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.MaxInFlight = n
+	}
+}
+
+// tokenBucket is a context-aware token bucket limiter.
+type tokenBucket struct {
+	rate  float64 // tokens per second
+	burst float64
+	clock func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64, burst int, clock func() time.Time) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rps))
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	return &tokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst), clock: clock}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it. The balance may go negative, queueing callers in order.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock()
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token that won't be used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// wait blocks until a token is available. It fails fast if the context
+// deadline would pass before then.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.cancel()
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// limitRoundTripper enforces a service's request budget and concurrency cap.
+type limitRoundTripper struct {
+	base   http.RoundTripper
+	name   string
+	bucket *tokenBucket  // optional
+	slots  chan struct{} // optional
+	queued metric.Float64Histogram
+	attrs  []attribute.KeyValue
+}
+
+func (rt *limitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	if rt.slots != nil {
+		select {
+		case rt.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for %q concurrency slot: %w", rt.name, ctx.Err())
+		}
+	}
+
+	if rt.bucket != nil {
+		if err := rt.bucket.wait(ctx); err != nil {
+			rt.release()
+			return nil, fmt.Errorf("waiting for %q rate limit: %w", rt.name, err)
+		}
+	}
+
+	if rt.queued != nil {
+		rt.queued.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(rt.attrs...))
+	}
+
+	resp, err := rt.base.RoundTrip(req)
+	if rt.slots == nil {
+		return resp, err
+	}
+	if err != nil || resp.Body == nil {
+		rt.release()
+		return resp, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: rt.release}
+	return resp, nil
+}
+
+func (rt *limitRoundTripper) release() {
+	if rt.slots != nil {
+		<-rt.slots
+	}
+}
+
+// releaseOnClose frees a concurrency slot once the body is closed or fully read.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnClose) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
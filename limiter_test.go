@@ -0,0 +1,138 @@
+package volt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("allows burst then spaces requests", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newTokenBucket(10, 2, clock.Now)
+
+		assertEqual(t, time.Duration(0), b.reserve())
+		assertEqual(t, time.Duration(0), b.reserve())
+		assertEqual(t, 100*time.Millisecond, b.reserve())
+		assertEqual(t, 200*time.Millisecond, b.reserve())
+
+		clock.Advance(time.Second)
+		assertEqual(t, time.Duration(0), b.reserve())
+	})
+
+	t.Run("burst defaults to rate rounded up", func(t *testing.T) {
+		b := newTokenBucket(2.5, 0, nil)
+		assertEqual(t, 3.0, b.burst)
+	})
+
+	t.Run("returns token when the context ends", func(t *testing.T) {
+		clock := newFakeClock()
+		b := newTokenBucket(1, 1, clock.Now)
+		b.reserve()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assertTrue(t, errors.Is(b.wait(ctx), context.Canceled))
+
+		// The canceled waiter didn't consume the next token
+		assertEqual(t, time.Second, b.reserve())
+	})
+
+	t.Run("fails fast when the deadline is too close", func(t *testing.T) {
+		b := newTokenBucket(1, 1, nil)
+		b.reserve()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		assertTrue(t, errors.Is(b.wait(ctx), context.DeadlineExceeded))
+		assertTrue(t, time.Since(start) < 10*time.Millisecond)
+	})
+}
+
+func TestLimitRoundTripper(t *testing.T) {
+	ok := &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	}}
+
+	t.Run("holds concurrency slot until body is closed", func(t *testing.T) {
+		rt := &limitRoundTripper{base: ok, name: "billing", slots: make(chan struct{}, 1)}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		first, err := rt.RoundTrip(req)
+		assertNil(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = rt.RoundTrip(req.WithContext(ctx))
+		assertTrue(t, errors.Is(err, context.DeadlineExceeded))
+
+		first.Body.Close()
+		second, err := rt.RoundTrip(req)
+		assertNil(t, err)
+		second.Body.Close()
+	})
+
+	t.Run("releases slot on transport error", func(t *testing.T) {
+		failing := &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}}
+		rt := &limitRoundTripper{base: failing, slots: make(chan struct{}, 1)}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		rt.RoundTrip(req)
+		_, err := rt.RoundTrip(req)
+		assertEqual(t, "connection refused", err.Error())
+	})
+
+	t.Run("records queued time", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		queued, _ := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test").Float64Histogram("queued")
+		rt := &limitRoundTripper{base: ok, bucket: newTokenBucket(50, 1, nil), queued: queued}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		for i := 0; i < 2; i++ {
+			resp, err := rt.RoundTrip(req)
+			assertNil(t, err)
+			resp.Body.Close()
+		}
+
+		var rm metricdata.ResourceMetrics
+		assertNil(t, reader.Collect(context.Background(), &rm))
+		hist := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+		assertEqual(t, uint64(2), hist.DataPoints[0].Count)
+		assertTrue(t, hist.DataPoints[0].Sum >= 0.015)
+	})
+}
+
+func TestHTTPServiceRateLimit(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	app := newTestApp()
+	RegisterHTTPService(app, "quota", func(c *http.Client) *http.Client { return c },
+		WithHTTPRateLimit(20, 1),
+		WithHTTPMaxInFlight(2),
+	)
+	assertNil(t, app.registry.Initialize(context.Background(), app))
+	client := app.registry.MustGet("quota").(*http.Client)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(upstream.URL)
+		assertNil(t, err)
+		resp.Body.Close()
+	}
+	assertTrue(t, time.Since(start) >= 90*time.Millisecond)
+}
@@ -0,0 +1,130 @@
+package volt
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// --- Operation Metrics ---
+
+// Attribute keys for per-operation metrics.
+const (
+	attrOperationID = attribute.Key("operation.id")
+	attrStatusClass = attribute.Key("http.response.status_class")
+)
+
+// durationBuckets are the semantic convention boundaries for HTTP durations
+// in seconds.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// operationMetrics holds the RED instruments recorded for every operation.
+type operationMetrics struct {
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+	active   metric.Int64UpDownCounter
+}
+
+func newOperationMetrics(meter metric.Meter) (*operationMetrics, error) {
+	requests, err := meter.Int64Counter("http.server.operation.requests",
+		metric.WithDescription("Requests handled per operation"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter("http.server.operation.errors",
+		metric.WithDescription("Requests per operation that ended with a 5xx status"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram("http.server.operation.duration",
+		metric.WithDescription("Duration of requests per operation"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+	active, err := meter.Int64UpDownCounter("http.server.operation.active_requests",
+		metric.WithDescription("Requests per operation currently in flight"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &operationMetrics{requests: requests, errors: errors, duration: duration, active: active}, nil
+}
+
+// operationMetricsMiddleware records RED metrics per operation, keyed by
+// OperationID, route template, method and status class. It also names the
+// server span after the route and adds the route to otelhttp's metrics.
+func (a *App) operationMetricsMiddleware() func(ctx huma.Context, next func(huma.Context)) {
+	metrics, err := newOperationMetrics(a.otel.Meter("volt"))
+	if err != nil {
+		a.logger.Error("failed to create operation metrics", "error", err)
+		return func(ctx huma.Context, next func(huma.Context)) { next(ctx) }
+	}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		route := []attribute.KeyValue{
+			attrOperationID.String(op.OperationID),
+			semconv.HTTPRoute(op.Path),
+			semconv.HTTPRequestMethodKey.String(op.Method),
+		}
+
+		span := trace.SpanFromContext(ctx.Context())
+		span.SetName(op.Method + " " + op.Path)
+		span.SetAttributes(semconv.HTTPRoute(op.Path))
+		if labeler, ok := otelhttp.LabelerFromContext(ctx.Context()); ok {
+			labeler.Add(semconv.HTTPRoute(op.Path))
+		}
+
+		reqCtx := ctx.Context()
+		inFlight := metric.WithAttributes(route...)
+		metrics.active.Add(reqCtx, 1, inFlight)
+		start := time.Now()
+
+		defer func() {
+			// A panic further down fails the request; it's re-raised for the
+			// recoverer to answer
+			rec := recover()
+			status := ctx.Status()
+			if rec != nil {
+				status = http.StatusInternalServerError
+			}
+			attrs := route
+			if status != 0 {
+				attrs = append(route, attrStatusClass.String(statusClass(status)))
+			}
+
+			metrics.active.Add(reqCtx, -1, inFlight)
+			metrics.requests.Add(reqCtx, 1, metric.WithAttributes(attrs...))
+			metrics.duration.Record(reqCtx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+			if status >= 500 {
+				metrics.errors.Add(reqCtx, 1, metric.WithAttributes(attrs...))
+			}
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+
+		next(ctx)
+	}
+}
+
+// statusClass returns the class of an HTTP status, e.g. "2xx".
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
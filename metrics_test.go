@@ -0,0 +1,113 @@
+package volt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestOperationMetrics(t *testing.T) {
+	app, mem := newInMemoryApp(t)
+
+	type userInput struct {
+		ID string `path:"id"`
+	}
+	var inFlight float64
+	Register(app, Operation{
+		Method:      "GET",
+		Path:        "/users/{id}",
+		OperationID: "get-user",
+	}, func(ctx context.Context, in *userInput) (*struct{}, error) {
+		switch in.ID {
+		case "missing":
+			return nil, ErrNotFound("user not found")
+		case "broken":
+			return nil, errors.New("database unavailable")
+		}
+		points := mem.DataPoints("http.server.operation.active_requests", attribute.String("operation.id", "get-user"))
+		if len(points) == 1 {
+			inFlight = points[0].Value
+		}
+		return nil, nil
+	})
+
+	for _, id := range []string{"ada", "bob", "missing", "broken"} {
+		serveApp(app, "GET", "/users/"+id)
+	}
+
+	count := func(name, class string) float64 {
+		points := mem.DataPoints(name,
+			attribute.String("operation.id", "get-user"),
+			attribute.String("http.route", "/users/{id}"),
+			attribute.String("http.request.method", "GET"),
+			attribute.String("http.response.status_class", class),
+		)
+		if len(points) != 1 {
+			return 0
+		}
+		return points[0].Value
+	}
+
+	t.Run("requests, errors and durations by status class", func(t *testing.T) {
+		assertEqual(t, 2.0, count("http.server.operation.requests", "2xx"))
+		assertEqual(t, 1.0, count("http.server.operation.requests", "4xx"))
+		assertEqual(t, 1.0, count("http.server.operation.requests", "5xx"))
+		assertEqual(t, 1.0, count("http.server.operation.errors", "5xx"))
+		assertEqual(t, 0.0, count("http.server.operation.errors", "4xx"))
+
+		durations := mem.DataPoints("http.server.operation.duration", attribute.String("http.response.status_class", "2xx"))
+		assertEqual(t, 1, len(durations))
+		assertEqual(t, uint64(2), durations[0].Count)
+	})
+
+	t.Run("in-flight requests", func(t *testing.T) {
+		assertEqual(t, 1.0, inFlight)
+		points := mem.DataPoints("http.server.operation.active_requests", attribute.String("operation.id", "get-user"))
+		assertEqual(t, 1, len(points))
+		assertEqual(t, 0.0, points[0].Value)
+	})
+
+	t.Run("spans are named after the route", func(t *testing.T) {
+		spans := mem.Spans(SpanNamed("GET /users/{id}"), SpanWithAttributes(attribute.String("http.route", "/users/{id}")))
+		assertEqual(t, 4, len(spans))
+	})
+
+	t.Run("route is added to otelhttp metrics", func(t *testing.T) {
+		points := mem.DataPoints("http.server.request.duration", attribute.String("http.route", "/users/{id}"))
+		assertTrue(t, len(points) > 0)
+	})
+
+	t.Run("panics in middleware count as errors", func(t *testing.T) {
+		group := app.Group("/admin").UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+			panic("middleware broke")
+		})
+		RegisterGroup(group, Operation{Method: "GET", Path: "/stats", OperationID: "admin-stats"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+			return nil, nil
+		})
+
+		assertEqual(t, http.StatusInternalServerError, serveApp(app, "GET", "/admin/stats"))
+		requests := mem.DataPoints("http.server.operation.requests", attribute.String("operation.id", "admin-stats"))
+		assertEqual(t, 1, len(requests))
+		class, _ := requests[0].Attributes.Value("http.response.status_class")
+		assertEqual(t, "5xx", class.AsString())
+		assertEqual(t, 1, len(mem.DataPoints("http.server.operation.errors", attribute.String("operation.id", "admin-stats"))))
+		active := mem.DataPoints("http.server.operation.active_requests", attribute.String("operation.id", "admin-stats"))
+		assertEqual(t, 0.0, active[0].Value)
+	})
+
+	t.Run("unmatched routes keep the default span name", func(t *testing.T) {
+		assertEqual(t, http.StatusNotFound, serveApp(app, "GET", "/nope"))
+		_, ok := mem.Span(SpanNamed("http.request"))
+		assertTrue(t, ok)
+	})
+}
+
+func TestStatusClass(t *testing.T) {
+	assertEqual(t, "2xx", statusClass(204))
+	assertEqual(t, "4xx", statusClass(499))
+	assertEqual(t, "5xx", statusClass(503))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 	"strings"
-	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -147,69 +146,6 @@ func CORS(config CORSConfig) func(http.Handler) http.Handler {
 	}
 }
 
-// --- Rate Limiting Middleware ---
-
-// RateLimitConfig configures rate limiting.
-type RateLimitConfig struct {
-	// Requests per window
-	Requests int
-
-	// Time window
-	Window time.Duration
-
-	// Key extractor (default: IP address)
-	KeyFunc func(r *http.Request) string
-
-	// Store for rate limit state (default: in-memory)
-	Store RateLimitStore
-}
-
-// RateLimitStore interface for rate limit state storage.
-type RateLimitStore interface {
-	// Allow checks if request is allowed and increments counter
-	Allow(key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time)
-}
-
-// InMemoryRateLimitStore is a simple in-memory rate limit store.
-type InMemoryRateLimitStore struct {
-	// Implementation would use sync.Map with cleanup
-}
-
-func (s *InMemoryRateLimitStore) Allow(key string, limit int, window time.Duration) (bool, int, time.Time) {
-	// Simplified - real implementation would track per-key counts
-	return true, limit, time.Now().Add(window)
-}
-
-// RateLimit creates a rate limiting middleware.
-func RateLimit(config RateLimitConfig) func(http.Handler) http.Handler {
-	if config.KeyFunc == nil {
-		config.KeyFunc = func(r *http.Request) string {
-			return r.RemoteAddr
-		}
-	}
-	if config.Store == nil {
-		config.Store = &InMemoryRateLimitStore{}
-	}
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			key := config.KeyFunc(r)
-			allowed, remaining, resetAt := config.Store.Allow(key, config.Requests, config.Window)
-
-			w.Header().Set("X-RateLimit-Limit", string(rune(config.Requests)))
-			w.Header().Set("X-RateLimit-Remaining", string(rune(remaining)))
-			w.Header().Set("X-RateLimit-Reset", resetAt.Format(time.RFC3339))
-
-			if !allowed {
-				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
 // --- Security Headers Middleware ---
 
 // SecureHeaders adds security headers to responses.
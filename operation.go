@@ -3,8 +3,10 @@ package volt
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
 )
 
 // Operation defines metadata for an API operation.
@@ -76,6 +78,12 @@ type Handler[I, O any] func(ctx context.Context, input *I) (*O, error)
 //	    // Implementation
 //	})
 func Register[I, O any](app *App, op Operation, handler Handler[I, O]) {
+	register(app, app.api, op, nil, handler)
+}
+
+// register builds the Huma operation and registers it on api, which is either
+// the app's API or a group view of it.
+func register[I, O any](app *App, api huma.API, op Operation, middlewares huma.Middlewares, handler Handler[I, O]) {
 	// Build huma operation
 	humaOp := huma.Operation{
 		Method:       op.Method,
@@ -87,14 +95,25 @@ func Register[I, O any](app *App, op Operation, handler Handler[I, O]) {
 		Deprecated:   op.Deprecated,
 		Security:     op.Security,
 		MaxBodyBytes: op.MaxBodyBytes,
+		Middlewares:  middlewares,
 	}
 
 	if op.Metadata != nil {
 		humaOp.Metadata = op.Metadata
 	}
 
+	// Document per-operation rate limits
+	if limit, ok := op.Metadata["ratelimit"].(OperationRateLimit); ok {
+		documentRateLimit(&humaOp, limit)
+	}
+
+	// Document the error codes the operation may return
+	if codes, ok := op.Metadata["errors"].([]ErrorCode); ok {
+		documentErrorCodes(&humaOp, api.OpenAPI().Components.Schemas, codes)
+	}
+
 	// Register with Huma, wrapping our handler
-	huma.Register(app.api, humaOp, func(ctx context.Context, input *I) (*O, error) {
+	huma.Register(api, humaOp, func(ctx context.Context, input *I) (output *O, err error) {
 		// Inject our enhanced context with service access
 		voltCtx := &Context{
 			Context:  ctx,
@@ -102,7 +121,22 @@ func Register[I, O any](app *App, op Operation, handler Handler[I, O]) {
 			logger:   app.logger,
 		}
 
-		return handler(voltCtx, input)
+		// Recover panics here rather than in the router so the response
+		// goes through Huma and the operation's metrics see the 500
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				output, err = nil, problemFromError(voltCtx, app.recoverPanic(voltCtx, humaOp.OperationID, rec))
+			}
+		}()
+
+		output, err = handler(voltCtx, input)
+		if err != nil {
+			return nil, app.handleError(voltCtx, humaOp.OperationID, err)
+		}
+		return output, nil
 	})
 }
 
@@ -112,11 +146,32 @@ func RegisterSimple(app *App, method, path string, handler http.HandlerFunc) {
 	app.router.Method(method, path, handler)
 }
 
-// Group creates a route group with shared middleware or prefix.
+// Group creates a route group with shared middleware, prefix and operation
+// defaults. Groups can be nested; a child inherits everything from its parent.
+//
+// Example:
+//
+//	admin := app.Group("/admin", requireSession).
+//	    WithTags("admin").
+//	    WithAuthz(volt.AuthzPermission("admin"))
+//
+//	volt.RegisterGroup(admin, volt.Operation{
+//	    Method: "DELETE",
+//	    Path:   "/users/{id}",
+//	}, handleDeleteUser)
 type Group struct {
 	app    *App
 	prefix string
 	mw     []func(http.Handler) http.Handler
+
+	// Huma middleware applied to the group's operations
+	humaMW huma.Middlewares
+
+	// Defaults merged into each operation
+	tags       []string
+	security   []map[string][]string
+	authz      *AuthzRequirement
+	deprecated bool
 }
 
 // Group creates a new route group.
@@ -128,11 +183,114 @@ func (a *App) Group(prefix string, middlewares ...func(http.Handler) http.Handle
 	}
 }
 
+// Group creates a nested group. The child's prefix is appended to the
+// parent's, and its middleware runs after the parent's.
+func (g *Group) Group(prefix string, middlewares ...func(http.Handler) http.Handler) *Group {
+	child := *g
+	child.prefix = g.prefix + prefix
+	child.mw = append(append([]func(http.Handler) http.Handler{}, g.mw...), middlewares...)
+	child.humaMW = append(huma.Middlewares{}, g.humaMW...)
+	child.tags = append([]string{}, g.tags...)
+	return &child
+}
+
+// Use adds HTTP middleware to the group.
+func (g *Group) Use(middlewares ...func(http.Handler) http.Handler) *Group {
+	g.mw = append(g.mw, middlewares...)
+	return g
+}
+
+// UseMiddleware adds Huma middleware to the group.
+func (g *Group) UseMiddleware(middlewares ...func(ctx huma.Context, next func(huma.Context))) *Group {
+	g.humaMW = append(g.humaMW, middlewares...)
+	return g
+}
+
+// WithTags adds OpenAPI tags to every operation in the group.
+func (g *Group) WithTags(tags ...string) *Group {
+	g.tags = append(g.tags, tags...)
+	return g
+}
+
+// WithSecurity sets the security requirements for operations that don't declare their own.
+func (g *Group) WithSecurity(security ...map[string][]string) *Group {
+	g.security = security
+	return g
+}
+
+// WithAuthz sets the authorization requirement for operations that don't declare their own.
+func (g *Group) WithAuthz(requirement AuthzRequirement) *Group {
+	g.authz = &requirement
+	return g
+}
+
+// WithDeprecated marks every operation in the group as deprecated.
+func (g *Group) WithDeprecated() *Group {
+	g.deprecated = true
+	return g
+}
+
+// apply merges the group's prefix and defaults into op.
+func (g *Group) apply(op Operation) Operation {
+	op.Path = g.prefix + op.Path
+
+	if len(g.tags) > 0 {
+		tags := append([]string{}, g.tags...)
+		for _, tag := range op.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		op.Tags = tags
+	}
+
+	if op.Security == nil {
+		op.Security = g.security
+	}
+
+	if g.authz != nil {
+		if _, ok := op.Metadata["authz"].(AuthzRequirement); !ok {
+			// Copy so the caller's metadata map isn't shared across operations
+			metadata := make(map[string]any, len(op.Metadata)+1)
+			for k, v := range op.Metadata {
+				metadata[k] = v
+			}
+			op.Metadata = metadata
+			op = WithAuthz(op, *g.authz)
+		}
+	}
+
+	op.Deprecated = op.Deprecated || g.deprecated
+
+	return op
+}
+
+// api returns a view of the app's API whose routes are mounted behind the
+// group's HTTP middleware.
+func (g *Group) api() huma.API {
+	if len(g.mw) == 0 {
+		return g.app.api
+	}
+	return &groupAPI{
+		API:     g.app.api,
+		adapter: humachi.NewAdapter(g.app.router.With(g.mw...)),
+	}
+}
+
+// groupAPI shares the app's OpenAPI document and Huma middleware but routes
+// through a chi router with the group's middleware applied.
+type groupAPI struct {
+	huma.API
+	adapter huma.Adapter
+}
+
+func (g *groupAPI) Adapter() huma.Adapter {
+	return g.adapter
+}
+
 // RegisterGroup registers an operation within the group.
 func RegisterGroup[I, O any](g *Group, op Operation, handler Handler[I, O]) {
-	// Prepend group prefix to path
-	op.Path = g.prefix + op.Path
-	Register(g.app, op, handler)
+	register(g.app, g.api(), g.apply(op), g.humaMW, handler)
 }
 
 // --- Common Input/Output Patterns ---
@@ -148,14 +306,6 @@ type StatusOutput struct {
 	}
 }
 
-// ErrorModel represents an error response.
-type ErrorModel struct {
-	Status  int    `json:"status" doc:"HTTP status code"`
-	Title   string `json:"title" doc:"Error title"`
-	Detail  string `json:"detail,omitempty" doc:"Detailed error message"`
-	TraceID string `json:"trace_id,omitempty" doc:"Trace ID for debugging"`
-}
-
 // PaginatedInput provides common pagination parameters.
 type PaginatedInput struct {
 	Page     int `query:"page" default:"1" minimum:"1" doc:"Page number"`
@@ -169,59 +319,3 @@ type PaginatedMeta struct {
 	TotalItems int `json:"total_items"`
 	TotalPages int `json:"total_pages"`
 }
-
-// --- Health Check ---
-
-// HealthInput is empty for health checks.
-type HealthInput struct{}
-
-// HealthOutput represents health check response.
-type HealthOutput struct {
-	Body struct {
-		Status    string            `json:"status" example:"healthy"`
-		Version   string            `json:"version,omitempty"`
-		Checks    map[string]string `json:"checks,omitempty"`
-		Timestamp string            `json:"timestamp,omitempty"`
-	}
-}
-
-// RegisterHealthCheck adds a health check endpoint.
-func RegisterHealthCheck(app *App, path string, checks ...HealthChecker) {
-	if path == "" {
-		path = "/health"
-	}
-
-	Register(app, Operation{
-		Method:  "GET",
-		Path:    path,
-		Summary: "Health check",
-		Tags:    []string{"health"},
-	}, func(ctx context.Context, input *HealthInput) (*HealthOutput, error) {
-		out := &HealthOutput{}
-		out.Body.Status = "healthy"
-		out.Body.Version = app.config.Version
-		out.Body.Checks = make(map[string]string)
-
-		for _, check := range checks {
-			name, status := check.Check(ctx)
-			out.Body.Checks[name] = status
-			if status != "ok" && status != "healthy" {
-				out.Body.Status = "degraded"
-			}
-		}
-
-		return out, nil
-	})
-}
-
-// HealthChecker interface for custom health checks.
-type HealthChecker interface {
-	Check(ctx context.Context) (name string, status string)
-}
-
-// HealthCheckFunc is a function adapter for HealthChecker.
-type HealthCheckFunc func(ctx context.Context) (name string, status string)
-
-func (f HealthCheckFunc) Check(ctx context.Context) (string, string) {
-	return f(ctx)
-}
@@ -2,7 +2,12 @@ package volt
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
 )
 
 func TestOperation(t *testing.T) {
@@ -66,30 +71,6 @@ func TestPaginatedMeta(t *testing.T) {
 	})
 }
 
-func TestHealthCheckFunc(t *testing.T) {
-	t.Run("adapts function to HealthChecker interface", func(t *testing.T) {
-		checker := HealthCheckFunc(func(ctx context.Context) (string, string) {
-			return "database", "ok"
-		})
-
-		name, status := checker.Check(context.Background())
-
-		assertEqual(t, "database", name)
-		assertEqual(t, "ok", status)
-	})
-
-	t.Run("can return unhealthy status", func(t *testing.T) {
-		checker := HealthCheckFunc(func(ctx context.Context) (string, string) {
-			return "redis", "connection failed"
-		})
-
-		name, status := checker.Check(context.Background())
-
-		assertEqual(t, "redis", name)
-		assertEqual(t, "connection failed", status)
-	})
-}
-
 func TestStatusOutput(t *testing.T) {
 	t.Run("can hold status response", func(t *testing.T) {
 		output := StatusOutput{}
@@ -135,3 +116,105 @@ func TestGroup(t *testing.T) {
 		assertEqual(t, app, group.app)
 	})
 }
+
+func TestRegisterGroup(t *testing.T) {
+	ok := func(ctx context.Context, in *EmptyInput) (*StatusOutput, error) {
+		out := &StatusOutput{}
+		out.Body.Status = "ok"
+		return out, nil
+	}
+
+	// tag returns an HTTP middleware that records its name in a response header
+	tag := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Chain", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	do := func(app *App, method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	t.Run("applies group HTTP middleware only to group routes", func(t *testing.T) {
+		app := newTestApp()
+		api := app.Group("/api", tag("api"))
+		RegisterGroup(api, Operation{Method: "GET", Path: "/items"}, ok)
+		Register(app, Operation{Method: "GET", Path: "/items"}, ok)
+
+		rec := do(app, "GET", "/api/items")
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "api", rec.Header().Get("X-Chain"))
+
+		rec = do(app, "GET", "/items")
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "", rec.Header().Get("X-Chain"))
+	})
+
+	t.Run("nested groups join prefixes and run parent middleware first", func(t *testing.T) {
+		app := newTestApp()
+		v2 := app.Group("/api", tag("api")).Group("/v2", tag("v2"))
+		RegisterGroup(v2, Operation{Method: "GET", Path: "/items"}, ok)
+
+		rec := do(app, "GET", "/api/v2/items")
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "api,v2", strings.Join(rec.Header().Values("X-Chain"), ","))
+	})
+
+	t.Run("applies group Huma middleware", func(t *testing.T) {
+		app := newTestApp()
+		g := app.Group("/api").UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+			ctx.SetHeader("X-Group", ctx.Operation().OperationID)
+			next(ctx)
+		})
+		RegisterGroup(g, Operation{Method: "GET", Path: "/items", OperationID: "list-items"}, ok)
+
+		rec := do(app, "GET", "/api/items")
+		assertEqual(t, "list-items", rec.Header().Get("X-Group"))
+	})
+
+	t.Run("merges defaults into operations", func(t *testing.T) {
+		app := newTestApp()
+		security := map[string][]string{"bearer": {}}
+		g := app.Group("/admin").
+			WithTags("admin").
+			WithSecurity(security).
+			WithDeprecated()
+		RegisterGroup(g, Operation{Method: "GET", Path: "/users", Tags: []string{"users", "admin"}}, ok)
+		RegisterGroup(g, Operation{
+			Method:   "GET",
+			Path:     "/public",
+			Security: []map[string][]string{},
+		}, ok)
+
+		users := app.API().OpenAPI().Paths["/admin/users"].Get
+		assertEqual(t, "admin,users", strings.Join(users.Tags, ","))
+		assertEqual(t, 1, len(users.Security))
+		assertTrue(t, users.Deprecated)
+
+		public := app.API().OpenAPI().Paths["/admin/public"].Get
+		assertEqual(t, 0, len(public.Security))
+	})
+
+	t.Run("applies group authz unless operation declares its own", func(t *testing.T) {
+		app := newTestApp()
+		var seen []string
+		SetAuthzPolicy(app, AuthzPolicyFunc(func(ctx context.Context, req AuthzRequest, requirement AuthzRequirement) error {
+			seen = append(seen, requirement.Permission)
+			return nil
+		}))
+
+		g := app.Group("/admin").WithAuthz(AuthzPermission("admin"))
+		RegisterGroup(g, Operation{Method: "GET", Path: "/a"}, ok)
+		RegisterGroup(g, WithAuthz(Operation{Method: "GET", Path: "/b"}, AuthzPermission("viewer")), ok)
+
+		do(app, "GET", "/admin/a")
+		do(app, "GET", "/admin/b")
+
+		assertEqual(t, "admin,viewer", strings.Join(seen, ","))
+	})
+}
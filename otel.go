@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
@@ -31,12 +32,19 @@ type OTELProvider struct {
 	meterProvider  *sdkmetric.MeterProvider
 	loggerProvider *sdklog.LoggerProvider
 	logger         *slog.Logger
+	metricsHandler http.Handler
 }
 
 // NewOTELProvider creates and configures OpenTelemetry providers.
 func NewOTELProvider(config OTELConfig) (*OTELProvider, error) {
 	ctx := context.Background()
 
+	switch config.Exporter {
+	case "", ExporterOTLP, ExporterStdout:
+	default:
+		return nil, fmt.Errorf("unsupported OTEL exporter %q", config.Exporter)
+	}
+
 	// Build resource with service information
 	res, err := buildResource(config)
 	if err != nil {
@@ -103,18 +111,24 @@ func buildResource(config OTELConfig) (*resource.Resource, error) {
 		attrs = append(attrs, attribute.String(k, v))
 	}
 
+	// Schemaless, so the merge doesn't conflict with the SDK's schema version
 	return resource.Merge(
 		resource.Default(),
-		resource.NewWithAttributes(semconv.SchemaURL, attrs...),
+		resource.NewSchemaless(attrs...),
 	)
 }
 
-// setupTraceProvider creates the trace provider with OTLP exporter.
+// setupTraceProvider creates the trace provider with the configured exporter.
 func (p *OTELProvider) setupTraceProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
-	exporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint(p.config.CollectorURL),
-		otlptracegrpc.WithInsecure(), // Use WithTLSCredentials in production
-	)
+	// In-memory spans are exported synchronously so tests see them on End
+	if p.config.InMemory != nil {
+		return sdktrace.NewTracerProvider(
+			sdktrace.WithSyncer(p.config.InMemory.spans),
+			sdktrace.WithResource(p.resource),
+		), nil
+	}
+
+	exporter, err := p.newTraceExporter(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -140,34 +154,51 @@ func (p *OTELProvider) setupTraceProvider(ctx context.Context) (*sdktrace.Tracer
 	return tp, nil
 }
 
-// setupMeterProvider creates the meter provider with OTLP exporter.
+// setupMeterProvider creates the meter provider with the configured exporter
+// and, if enabled, a Prometheus reader over the same instruments.
 func (p *OTELProvider) setupMeterProvider(ctx context.Context) (*sdkmetric.MeterProvider, error) {
-	exporter, err := otlpmetricgrpc.New(ctx,
-		otlpmetricgrpc.WithEndpoint(p.config.CollectorURL),
-		otlpmetricgrpc.WithInsecure(),
-	)
-	if err != nil {
-		return nil, err
+	var reader sdkmetric.Reader
+	if p.config.InMemory != nil {
+		reader = p.config.InMemory.reader
+	} else {
+		exporter, err := p.newMetricExporter(ctx)
+		if err != nil {
+			return nil, err
+		}
+		reader = sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(30*time.Second),
+		)
 	}
 
-	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(
-			sdkmetric.NewPeriodicReader(exporter,
-				sdkmetric.WithInterval(30*time.Second),
-			),
-		),
+	opts := []sdkmetric.Option{
+		sdkmetric.WithReader(reader),
 		sdkmetric.WithResource(p.resource),
-	)
+	}
 
-	return mp, nil
+	if p.config.Prometheus {
+		// Own registry, so several apps in one process don't collide
+		registry := prometheus.NewRegistry()
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, fmt.Errorf("prometheus exporter: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(exporter))
+		p.metricsHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	}
+
+	return sdkmetric.NewMeterProvider(opts...), nil
 }
 
-// setupLogProvider creates the log provider with OTLP exporter.
+// setupLogProvider creates the log provider with the configured exporter.
 func (p *OTELProvider) setupLogProvider(ctx context.Context) (*sdklog.LoggerProvider, error) {
-	exporter, err := otlploggrpc.New(ctx,
-		otlploggrpc.WithEndpoint(p.config.CollectorURL),
-		otlploggrpc.WithInsecure(),
-	)
+	if p.config.InMemory != nil {
+		return sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(sdklog.NewSimpleProcessor(logExporter{p.config.InMemory})),
+			sdklog.WithResource(p.resource),
+		), nil
+	}
+
+	exporter, err := p.newLogExporter(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -182,22 +213,33 @@ func (p *OTELProvider) setupLogProvider(ctx context.Context) (*sdklog.LoggerProv
 	return lp, nil
 }
 
-// TracerProvider returns the trace provider.
+// TracerProvider returns the trace provider, falling back to the global
+// provider when OTEL is not configured (including on a nil receiver).
 func (p *OTELProvider) TracerProvider() trace.TracerProvider {
-	if p.tracerProvider != nil {
+	if p != nil && p.tracerProvider != nil {
 		return p.tracerProvider
 	}
 	return otel.GetTracerProvider()
 }
 
-// MeterProvider returns the meter provider.
+// MeterProvider returns the meter provider, falling back to the global
+// provider when OTEL is not configured (including on a nil receiver).
 func (p *OTELProvider) MeterProvider() metric.MeterProvider {
-	if p.meterProvider != nil {
+	if p != nil && p.meterProvider != nil {
 		return p.meterProvider
 	}
 	return otel.GetMeterProvider()
 }
 
+// MetricsHandler returns the Prometheus scrape handler, or nil if the
+// Prometheus reader is not enabled.
+func (p *OTELProvider) MetricsHandler() http.Handler {
+	if p == nil {
+		return nil
+	}
+	return p.metricsHandler
+}
+
 // Logger returns the OTEL-bridged slog logger.
 func (p *OTELProvider) Logger() *slog.Logger {
 	if p.logger != nil {
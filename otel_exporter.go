@@ -0,0 +1,301 @@
+package volt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// Exporters for OTELConfig.Exporter.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// OTLP protocols for OTELConfig.Protocol.
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http/protobuf"
+)
+
+// Signals, as used in the per-signal OTEL_EXPORTER_OTLP_<SIGNAL>_* variables.
+const (
+	signalTraces  = "TRACES"
+	signalMetrics = "METRICS"
+	signalLogs    = "LOGS"
+)
+
+// --- Exporter Selection ---
+
+// exporterKind returns the exporter for signal. Without an explicit choice,
+// development environments without a collector print to stdout.
+func (p *OTELProvider) exporterKind(signal string) string {
+	if p.config.Exporter != "" {
+		return p.config.Exporter
+	}
+	if p.config.Environment == "development" && p.config.CollectorURL == "" &&
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_ENDPOINT") == "" {
+		return ExporterStdout
+	}
+	return ExporterOTLP
+}
+
+// otlpSettings holds the exporter options for one signal. Zero values leave
+// the setting to the exporter, which reads the OTEL_EXPORTER_OTLP_* and
+// OTEL_EXPORTER_OTLP_<SIGNAL>_* environment variables.
+type otlpSettings struct {
+	protocol    string
+	endpoint    string // host:port
+	urlPath     string // OTLP/HTTP only
+	insecure    bool
+	tlsConfig   *tls.Config
+	headers     map[string]string
+	compression string
+}
+
+// otlpSettings resolves the OTLP settings for signal. Per-signal environment
+// variables take precedence over CollectorURL, which is usually read from
+// the general OTEL_EXPORTER_OTLP_ENDPOINT.
+func (p *OTELProvider) otlpSettings(signal string) (otlpSettings, error) {
+	c := p.config
+	s := otlpSettings{
+		protocol:    getEnv("OTEL_EXPORTER_OTLP_"+signal+"_PROTOCOL", c.Protocol),
+		tlsConfig:   c.TLSConfig,
+		headers:     c.Headers,
+		compression: c.Compression,
+	}
+
+	switch s.protocol {
+	case "", OTLPProtocolGRPC:
+		s.protocol = OTLPProtocolGRPC
+	case OTLPProtocolHTTP, "http":
+		s.protocol = OTLPProtocolHTTP
+	default:
+		return s, fmt.Errorf("unsupported OTLP protocol %q", s.protocol)
+	}
+
+	switch s.compression {
+	case "", "none", "gzip":
+	default:
+		return s, fmt.Errorf("unsupported OTLP compression %q", s.compression)
+	}
+
+	envEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_" + signal + "_ENDPOINT")
+	scheme := ""
+	if envEndpoint == "" && c.CollectorURL != "" {
+		if strings.Contains(c.CollectorURL, "://") {
+			u, err := url.Parse(c.CollectorURL)
+			if err != nil {
+				return s, fmt.Errorf("invalid collector URL: %w", err)
+			}
+			scheme = u.Scheme
+			s.endpoint = u.Host
+			if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+				s.urlPath = path + "/v1/" + strings.ToLower(signal)
+			}
+		} else {
+			s.endpoint = c.CollectorURL
+		}
+	}
+
+	// Plaintext unless TLS is configured in code, via the environment, or
+	// by an https endpoint
+	tlsEnv := false
+	for _, key := range []string{"CERTIFICATE", "CLIENT_CERTIFICATE", "INSECURE"} {
+		if os.Getenv("OTEL_EXPORTER_OTLP_"+key) != "" || os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_"+key) != "" {
+			tlsEnv = true
+		}
+	}
+	if s.tlsConfig == nil && !tlsEnv && envEndpoint == "" && scheme != "https" {
+		s.insecure = true
+	}
+
+	return s, nil
+}
+
+// --- Exporters ---
+
+// newTraceExporter creates the span exporter selected by the config.
+func (p *OTELProvider) newTraceExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	if p.exporterKind(signalTraces) == ExporterStdout {
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	}
+
+	s, err := p.otlpSettings(signalTraces)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.protocol == OTLPProtocolHTTP {
+		var opts []otlptracehttp.Option
+		if s.endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(s.endpoint))
+		}
+		if s.urlPath != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(s.urlPath))
+		}
+		if s.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if s.tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(s.tlsConfig))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(s.headers))
+		}
+		switch s.compression {
+		case "gzip":
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		case "none":
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.NoCompression))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	var opts []otlptracegrpc.Option
+	if s.endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(s.endpoint))
+	}
+	if s.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if s.tlsConfig != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(s.tlsConfig)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(s.headers))
+	}
+	if s.compression == "gzip" {
+		opts = append(opts, otlptracegrpc.WithCompressor("gzip"))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newMetricExporter creates the metric exporter selected by the config.
+func (p *OTELProvider) newMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	if p.exporterKind(signalMetrics) == ExporterStdout {
+		return stdoutmetric.New(stdoutmetric.WithPrettyPrint())
+	}
+
+	s, err := p.otlpSettings(signalMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.protocol == OTLPProtocolHTTP {
+		var opts []otlpmetrichttp.Option
+		if s.endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(s.endpoint))
+		}
+		if s.urlPath != "" {
+			opts = append(opts, otlpmetrichttp.WithURLPath(s.urlPath))
+		}
+		if s.insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if s.tlsConfig != nil {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(s.tlsConfig))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(s.headers))
+		}
+		switch s.compression {
+		case "gzip":
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		case "none":
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.NoCompression))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	var opts []otlpmetricgrpc.Option
+	if s.endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(s.endpoint))
+	}
+	if s.insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if s.tlsConfig != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(s.tlsConfig)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(s.headers))
+	}
+	if s.compression == "gzip" {
+		opts = append(opts, otlpmetricgrpc.WithCompressor("gzip"))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// newLogExporter creates the log exporter selected by the config.
+func (p *OTELProvider) newLogExporter(ctx context.Context) (sdklog.Exporter, error) {
+	if p.exporterKind(signalLogs) == ExporterStdout {
+		return stdoutlog.New(stdoutlog.WithPrettyPrint())
+	}
+
+	s, err := p.otlpSettings(signalLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.protocol == OTLPProtocolHTTP {
+		var opts []otlploghttp.Option
+		if s.endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpoint(s.endpoint))
+		}
+		if s.urlPath != "" {
+			opts = append(opts, otlploghttp.WithURLPath(s.urlPath))
+		}
+		if s.insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if s.tlsConfig != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(s.tlsConfig))
+		}
+		if len(s.headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(s.headers))
+		}
+		switch s.compression {
+		case "gzip":
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		case "none":
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.NoCompression))
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+
+	var opts []otlploggrpc.Option
+	if s.endpoint != "" {
+		opts = append(opts, otlploggrpc.WithEndpoint(s.endpoint))
+	}
+	if s.insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if s.tlsConfig != nil {
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(s.tlsConfig)))
+	}
+	if len(s.headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(s.headers))
+	}
+	if s.compression == "gzip" {
+		opts = append(opts, otlploggrpc.WithCompressor("gzip"))
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
@@ -0,0 +1,136 @@
+package volt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExporterKind(t *testing.T) {
+	kind := func(config OTELConfig, signal string) string {
+		return (&OTELProvider{config: config}).exporterKind(signal)
+	}
+
+	assertEqual(t, ExporterStdout, kind(OTELConfig{Environment: "development"}, signalTraces))
+	assertEqual(t, ExporterOTLP, kind(OTELConfig{Environment: "development", CollectorURL: "localhost:4317"}, signalTraces))
+	assertEqual(t, ExporterOTLP, kind(OTELConfig{Environment: "production"}, signalTraces))
+	assertEqual(t, ExporterStdout, kind(OTELConfig{Environment: "production", Exporter: ExporterStdout}, signalTraces))
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://tempo:4318/v1/traces")
+	assertEqual(t, ExporterOTLP, kind(OTELConfig{Environment: "development"}, signalTraces))
+	assertEqual(t, ExporterStdout, kind(OTELConfig{Environment: "development"}, signalMetrics))
+
+	_, err := NewOTELProvider(OTELConfig{Exporter: "zipkin"})
+	assertTrue(t, err != nil && strings.Contains(err.Error(), `unsupported OTEL exporter "zipkin"`))
+}
+
+func TestOTLPSettings(t *testing.T) {
+	settings := func(t *testing.T, config OTELConfig, signal string) otlpSettings {
+		t.Helper()
+		s, err := (&OTELProvider{config: config}).otlpSettings(signal)
+		assertNil(t, err)
+		return s
+	}
+
+	t.Run("host and port default to plaintext gRPC", func(t *testing.T) {
+		s := settings(t, OTELConfig{CollectorURL: "collector:4317"}, signalTraces)
+		assertEqual(t, OTLPProtocolGRPC, s.protocol)
+		assertEqual(t, "collector:4317", s.endpoint)
+		assertTrue(t, s.insecure)
+	})
+
+	t.Run("https URL enables TLS and keeps the base path", func(t *testing.T) {
+		s := settings(t, OTELConfig{CollectorURL: "https://otel.example.com/otlp/", Protocol: OTLPProtocolHTTP}, signalMetrics)
+		assertEqual(t, OTLPProtocolHTTP, s.protocol)
+		assertEqual(t, "otel.example.com", s.endpoint)
+		assertEqual(t, "/otlp/v1/metrics", s.urlPath)
+		assertTrue(t, !s.insecure)
+	})
+
+	t.Run("TLS config disables plaintext", func(t *testing.T) {
+		s := settings(t, OTELConfig{CollectorURL: "collector:4317", TLSConfig: &tls.Config{}}, signalLogs)
+		assertTrue(t, !s.insecure)
+	})
+
+	t.Run("certificate environment variable disables plaintext", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_LOGS_CERTIFICATE", "/etc/otel/ca.pem")
+		assertTrue(t, !settings(t, OTELConfig{}, signalLogs).insecure)
+		assertTrue(t, settings(t, OTELConfig{}, signalTraces).insecure)
+	})
+
+	t.Run("per-signal environment variables take precedence", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://tempo:4318/v1/traces")
+		t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "http/protobuf")
+		config := OTELConfig{CollectorURL: "collector:4317", Protocol: OTLPProtocolGRPC}
+
+		traces := settings(t, config, signalTraces)
+		assertEqual(t, OTLPProtocolHTTP, traces.protocol)
+		assertEqual(t, "", traces.endpoint)
+		assertTrue(t, !traces.insecure)
+
+		metrics := settings(t, config, signalMetrics)
+		assertEqual(t, OTLPProtocolGRPC, metrics.protocol)
+		assertEqual(t, "collector:4317", metrics.endpoint)
+	})
+
+	t.Run("rejects unknown protocol and compression", func(t *testing.T) {
+		_, err := (&OTELProvider{config: OTELConfig{Protocol: "thrift"}}).otlpSettings(signalTraces)
+		assertTrue(t, err != nil)
+		_, err = (&OTELProvider{config: OTELConfig{Compression: "zstd"}}).otlpSettings(signalTraces)
+		assertTrue(t, err != nil)
+	})
+}
+
+func TestOTLPHTTPExport(t *testing.T) {
+	restoreOTELGlobals(t)
+
+	clientCert := newTestCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	type export struct {
+		path, auth, encoding, client string
+	}
+	exports := make(chan export, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports <- export{
+			path:     r.URL.Path,
+			auth:     r.Header.Get("Authorization"),
+			encoding: r.Header.Get("Content-Encoding"),
+			client:   r.TLS.PeerCertificates[0].Subject.CommonName,
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	provider, err := NewOTELProvider(OTELConfig{
+		ServiceName:     "volt-test",
+		CollectorURL:    server.URL + "/otlp",
+		Protocol:        OTLPProtocolHTTP,
+		TLSConfig:       &tls.Config{RootCAs: rootCAs, Certificates: []tls.Certificate{clientCert}},
+		Headers:         map[string]string{"Authorization": "Bearer otel-token"},
+		Compression:     "gzip",
+		TraceSampleRate: 1,
+		EnableTraces:    true,
+	})
+	assertNil(t, err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "work")
+	span.End()
+	assertNil(t, provider.Shutdown(context.Background()))
+
+	got := <-exports
+	assertEqual(t, "/otlp/v1/traces", got.path)
+	assertEqual(t, "Bearer otel-token", got.auth)
+	assertEqual(t, "gzip", got.encoding)
+	assertEqual(t, "volt-test-client", got.client)
+}
@@ -0,0 +1,259 @@
+package volt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// --- In-Memory Exporter ---
+
+// InMemoryExporter keeps spans, metrics and log records in memory so tests
+// can assert on the telemetry an app produces without a collector.
+//
+// Example:
+//
+//	mem := volt.NewInMemoryExporter()
+//	app := volt.New(volt.WithOTELInMemory(mem))
+//	// ... serve requests ...
+//	span, ok := mem.Span(volt.SpanNamed("GET /users/{id}"))
+type InMemoryExporter struct {
+	spans  *tracetest.InMemoryExporter
+	reader *sdkmetric.ManualReader
+
+	mu   sync.Mutex
+	logs []LogRecord
+}
+
+// NewInMemoryExporter creates an empty in-memory exporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{
+		spans:  tracetest.NewInMemoryExporter(),
+		reader: sdkmetric.NewManualReader(),
+	}
+}
+
+// Reset discards the recorded spans and log records. Metrics are
+// cumulative and are not affected.
+func (e *InMemoryExporter) Reset() {
+	e.spans.Reset()
+	e.mu.Lock()
+	e.logs = nil
+	e.mu.Unlock()
+}
+
+// --- Spans ---
+
+// SpanFilter selects recorded spans.
+type SpanFilter func(tracetest.SpanStub) bool
+
+// SpanNamed matches spans with the given name.
+func SpanNamed(name string) SpanFilter {
+	return func(s tracetest.SpanStub) bool {
+		return s.Name == name
+	}
+}
+
+// SpanWithAttributes matches spans that have all the given attributes.
+func SpanWithAttributes(attrs ...attribute.KeyValue) SpanFilter {
+	return func(s tracetest.SpanStub) bool {
+		return hasAttributes(attribute.NewSet(s.Attributes...), attrs)
+	}
+}
+
+// SpanChildOf matches direct children of parent.
+func SpanChildOf(parent tracetest.SpanStub) SpanFilter {
+	return func(s tracetest.SpanStub) bool {
+		return s.Parent.SpanID() == parent.SpanContext.SpanID() &&
+			s.Parent.TraceID() == parent.SpanContext.TraceID()
+	}
+}
+
+// Spans returns the ended spans matching all filters, in the order they ended.
+func (e *InMemoryExporter) Spans(filters ...SpanFilter) tracetest.SpanStubs {
+	var out tracetest.SpanStubs
+	for _, s := range e.spans.GetSpans() {
+		if matchSpan(s, filters) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Span returns the first ended span matching all filters.
+func (e *InMemoryExporter) Span(filters ...SpanFilter) (tracetest.SpanStub, bool) {
+	for _, s := range e.spans.GetSpans() {
+		if matchSpan(s, filters) {
+			return s, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+func matchSpan(s tracetest.SpanStub, filters []SpanFilter) bool {
+	for _, f := range filters {
+		if !f(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Metrics ---
+
+// DataPoint is a single metric data point. Value holds the value of sums
+// and gauges and the sum of histograms; Count is the number of histogram
+// observations.
+type DataPoint struct {
+	Attributes attribute.Set
+	Value      float64
+	Count      uint64
+}
+
+// Metrics collects the current state of all instruments.
+func (e *InMemoryExporter) Metrics(ctx context.Context) (metricdata.ResourceMetrics, error) {
+	var rm metricdata.ResourceMetrics
+	err := e.reader.Collect(ctx, &rm)
+	return rm, err
+}
+
+// Metric collects the instrument with the given name.
+func (e *InMemoryExporter) Metric(name string) (metricdata.Metrics, bool) {
+	rm, err := e.Metrics(context.Background())
+	if err != nil {
+		return metricdata.Metrics{}, false
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// DataPoints returns the data points of the named instrument that have all
+// the given attributes.
+func (e *InMemoryExporter) DataPoints(name string, attrs ...attribute.KeyValue) []DataPoint {
+	m, ok := e.Metric(name)
+	if !ok {
+		return nil
+	}
+
+	var points []DataPoint
+	add := func(set attribute.Set, value float64, count uint64) {
+		if hasAttributes(set, attrs) {
+			points = append(points, DataPoint{Attributes: set, Value: value, Count: count})
+		}
+	}
+
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, float64(dp.Value), 0)
+		}
+	case metricdata.Sum[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, dp.Value, 0)
+		}
+	case metricdata.Gauge[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, float64(dp.Value), 0)
+		}
+	case metricdata.Gauge[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, dp.Value, 0)
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, float64(dp.Sum), dp.Count)
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			add(dp.Attributes, dp.Sum, dp.Count)
+		}
+	}
+	return points
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// --- Logs ---
+
+// LogRecord is an exported log record.
+type LogRecord struct {
+	Time       time.Time
+	Severity   log.Severity
+	Message    string
+	Attributes map[string]log.Value
+	TraceID    trace.TraceID
+	SpanID     trace.SpanID
+}
+
+// Attr returns the string form of an attribute, or "" if it is not set.
+func (r LogRecord) Attr(key string) string {
+	if v, ok := r.Attributes[key]; ok {
+		return v.String()
+	}
+	return ""
+}
+
+// Logs returns the exported log records whose message is msg, or all
+// records if msg is empty.
+func (e *InMemoryExporter) Logs(msg string) []LogRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out []LogRecord
+	for _, r := range e.logs {
+		if msg == "" || r.Message == msg {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// logExporter adapts InMemoryExporter to sdklog.Exporter.
+type logExporter struct {
+	e *InMemoryExporter
+}
+
+func (x logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	x.e.mu.Lock()
+	defer x.e.mu.Unlock()
+	for i := range records {
+		r := &records[i]
+		rec := LogRecord{
+			Time:       r.Timestamp(),
+			Severity:   r.Severity(),
+			Message:    r.Body().String(),
+			Attributes: make(map[string]log.Value, r.AttributesLen()),
+			TraceID:    r.TraceID(),
+			SpanID:     r.SpanID(),
+		}
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			rec.Attributes[kv.Key] = kv.Value
+			return true
+		})
+		x.e.logs = append(x.e.logs, rec)
+	}
+	return nil
+}
+
+func (logExporter) Shutdown(context.Context) error   { return nil }
+func (logExporter) ForceFlush(context.Context) error { return nil }
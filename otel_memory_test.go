@@ -0,0 +1,104 @@
+package volt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newInMemoryApp creates an app exporting to memory.
+func newInMemoryApp(t *testing.T, opts ...Option) (*App, *InMemoryExporter) {
+	t.Helper()
+	restoreOTELGlobals(t)
+	mem := NewInMemoryExporter()
+	app := newTestApp(append([]Option{WithOTELInMemory(mem)}, opts...)...)
+	t.Cleanup(func() { app.otel.Shutdown(context.Background()) })
+	return app, mem
+}
+
+// restoreOTELGlobals resets the global providers NewOTELProvider installs
+// when the test ends.
+func restoreOTELGlobals(t *testing.T) {
+	tp, mp, lp := otel.GetTracerProvider(), otel.GetMeterProvider(), global.GetLoggerProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetMeterProvider(mp)
+		global.SetLoggerProvider(lp)
+	})
+}
+
+func TestInMemoryExporter(t *testing.T) {
+	app, mem := newInMemoryApp(t)
+	assertNotNil(t, app.otel)
+
+	orders, err := Counter("orders.created", "Orders created")
+	assertNil(t, err)
+
+	Register(app, Operation{Method: "POST", Path: "/orders"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+		ctx, span := StartSpan(ctx, "orders.save", trace.WithAttributes(attribute.String("order.kind", "retail")))
+		defer span.End()
+		orders.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "retail")))
+		app.Logger().InfoContext(ctx, "order saved", "order_id", "ord_1")
+		return nil, nil
+	})
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("POST", "/orders", nil))
+		assertEqual(t, http.StatusNoContent, rec.Code)
+	}
+
+	t.Run("spans", func(t *testing.T) {
+		assertEqual(t, 4, len(mem.Spans()))
+		assertEqual(t, 2, len(mem.Spans(SpanNamed("orders.save"))))
+
+		_, ok := mem.Span(SpanNamed("orders.save"), SpanWithAttributes(attribute.String("order.kind", "retail")))
+		assertTrue(t, ok)
+		_, ok = mem.Span(SpanWithAttributes(attribute.String("order.kind", "wholesale")))
+		assertTrue(t, !ok)
+
+		requests := mem.Spans(SpanNamed("POST /orders"))
+		assertEqual(t, 2, len(requests))
+		child, ok := mem.Span(SpanChildOf(requests[0]))
+		assertTrue(t, ok)
+		assertEqual(t, "orders.save", child.Name)
+		assertEqual(t, 0, len(mem.Spans(SpanChildOf(child))))
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		points := mem.DataPoints("orders.created", attribute.String("kind", "retail"))
+		assertEqual(t, 1, len(points))
+		assertEqual(t, 2.0, points[0].Value)
+		assertEqual(t, 0, len(mem.DataPoints("orders.created", attribute.String("kind", "wholesale"))))
+
+		durations := mem.DataPoints("http.server.request.duration")
+		assertEqual(t, 1, len(durations))
+		assertEqual(t, uint64(2), durations[0].Count)
+
+		_, ok := mem.Metric("does.not.exist")
+		assertTrue(t, !ok)
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		records := mem.Logs("order saved")
+		assertEqual(t, 2, len(records))
+		assertEqual(t, log.SeverityInfo, records[0].Severity)
+		assertEqual(t, "ord_1", records[0].Attr("order_id"))
+		assertTrue(t, records[0].TraceID.IsValid())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		mem.Reset()
+		assertEqual(t, 0, len(mem.Spans()))
+		assertEqual(t, 0, len(mem.Logs("")))
+		assertEqual(t, 1, len(mem.DataPoints("orders.created")))
+	})
+}
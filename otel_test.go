@@ -0,0 +1,78 @@
+package volt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestPrometheusEndpoint(t *testing.T) {
+	restoreOTELGlobals(t)
+	mem := NewInMemoryExporter()
+	app := newTestApp(WithOTELInMemory(mem), WithOTELPrometheus(""))
+	t.Cleanup(func() { app.otel.Shutdown(context.Background()) })
+
+	orders, err := Counter("orders.created", "Orders created")
+	assertNil(t, err)
+	Register(app, Operation{Method: "POST", Path: "/orders"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+		orders.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "retail")))
+		return nil, nil
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	assertEqual(t, http.StatusNoContent, serve("POST", "/orders").Code)
+
+	t.Run("same instruments in both pipelines", func(t *testing.T) {
+		rec := serve("GET", "/metrics")
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertTrue(t, strings.Contains(rec.Body.String(), `orders_created_total{kind="retail"`))
+		assertTrue(t, strings.Contains(rec.Body.String(), "http_server_request_duration_seconds"))
+
+		points := mem.DataPoints("orders.created")
+		assertEqual(t, 1, len(points))
+		assertEqual(t, 1.0, points[0].Value)
+	})
+
+	t.Run("scrapes are not logged or traced", func(t *testing.T) {
+		mem.Reset()
+		serve("GET", "/metrics")
+		assertEqual(t, 0, len(mem.Logs("request completed")))
+		assertEqual(t, 0, len(mem.Spans()))
+
+		serve("POST", "/orders")
+		assertEqual(t, 1, len(mem.Logs("request completed")))
+	})
+
+	t.Run("not in the OpenAPI spec", func(t *testing.T) {
+		_, ok := app.API().OpenAPI().Paths["/metrics"]
+		assertTrue(t, !ok)
+	})
+
+	t.Run("custom path", func(t *testing.T) {
+		custom := newTestApp(WithOTELInMemory(NewInMemoryExporter()), WithOTELPrometheus("/internal/metrics"))
+		t.Cleanup(func() { custom.otel.Shutdown(context.Background()) })
+
+		assertEqual(t, http.StatusOK, serveApp(custom, "GET", "/internal/metrics"))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		assertTrue(t, (*OTELProvider)(nil).MetricsHandler() == nil)
+		assertEqual(t, http.StatusNotFound, serveApp(newTestApp(), "GET", "/metrics"))
+	})
+}
+
+func serveApp(app *App, method, path string) int {
+	rec := httptest.NewRecorder()
+	app.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec.Code
+}
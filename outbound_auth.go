@@ -0,0 +1,297 @@
+package volt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --- Outbound Authentication ---
+
+// HTTPAuthenticator adds credentials to outbound requests of an HTTP
+// service. Authenticate is called on a copy of each request attempt, so it
+// may set headers freely.
+type HTTPAuthenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// HTTPAuthFunc is a function adapter for HTTPAuthenticator.
+type HTTPAuthFunc func(req *http.Request) error
+
+func (f HTTPAuthFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// WithHTTPAuth authenticates every request to the service.
+//
+// Example:
+//
+//	volt.RegisterHTTPService(app, "billing", billing.NewClient,
+//	    volt.WithHTTPAuth(volt.NewOAuth2ClientCredentials(volt.OAuth2Config{
+//	        TokenURL:     "https://auth.example.com/oauth/token",
+//	        ClientID:     os.Getenv("BILLING_CLIENT_ID"),
+//	        ClientSecret: os.Getenv("BILLING_CLIENT_SECRET"),
+//	        Scopes:       []string{"invoices:read"},
+//	    })),
+//	)
+func WithHTTPAuth(auth HTTPAuthenticator) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.Auth = auth
+	}
+}
+
+// WithHTTPClientCertificate presents the given certificates for mutual TLS.
+// It also applies to a transport given with WithHTTPTransport, which must
+// then be an *http.Transport.
+func WithHTTPClientCertificate(certs ...tls.Certificate) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.TLSConfig = cloneTLSConfig(c.TLSConfig)
+		c.TLSConfig.Certificates = append(c.TLSConfig.Certificates, certs...)
+	}
+}
+
+// WithHTTPRootCAs verifies the service's certificate against pool instead
+// of the system roots. Like WithHTTPClientCertificate, it also applies to a
+// transport given with WithHTTPTransport.
+func WithHTTPRootCAs(pool *x509.CertPool) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.TLSConfig = cloneTLSConfig(c.TLSConfig)
+		c.TLSConfig.RootCAs = pool
+	}
+}
+
+func cloneTLSConfig(config *tls.Config) *tls.Config {
+	if config == nil {
+		return &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return config.Clone()
+}
+
+// APIKeyAuth sets header to the key returned by provider on every request.
+// The provider is called each time, so it can return rotated keys (e.g.
+// read from a secret manager with its own caching).
+func APIKeyAuth(header string, provider func(ctx context.Context) (string, error)) HTTPAuthenticator {
+	return HTTPAuthFunc(func(req *http.Request) error {
+		key, err := provider(req.Context())
+		if err != nil {
+			return fmt.Errorf("api key: %w", err)
+		}
+		req.Header.Set(header, key)
+		return nil
+	})
+}
+
+// --- OAuth2 Client Credentials ---
+
+// OAuth2Config configures the OAuth2 client credentials grant (RFC 6749 §4.4).
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// Extra form parameters for the token request, e.g. "audience"
+	EndpointParams url.Values
+
+	// Send client credentials in the form body instead of HTTP Basic auth
+	AuthInParams bool
+
+	// Refresh tokens this long before they expire (default: 1 minute,
+	// capped at half the token lifetime)
+	RefreshBefore time.Duration
+
+	// Client for token requests (default: a client with a 10s timeout)
+	HTTPClient *http.Client
+
+	// Time source (default: time.Now). Override in tests.
+	Clock func() time.Time
+}
+
+// OAuth2ClientCredentials fetches and caches access tokens, refreshing them
+// shortly before they expire. It is safe for concurrent use; concurrent
+// requests share a single token fetch.
+type OAuth2ClientCredentials struct {
+	config OAuth2Config
+
+	mu        sync.Mutex
+	token     string
+	tokenType string
+	refreshAt time.Time
+	inflight  *tokenFetch // shared by callers while a fetch runs
+}
+
+// tokenFetch is a token request shared by concurrent callers.
+type tokenFetch struct {
+	done      chan struct{} // closed when the fetch finishes
+	token     string
+	tokenType string
+	err       error
+}
+
+// NewOAuth2ClientCredentials creates an authenticator for the client
+// credentials grant.
+func NewOAuth2ClientCredentials(config OAuth2Config) *OAuth2ClientCredentials {
+	if config.RefreshBefore <= 0 {
+		config.RefreshBefore = time.Minute
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &OAuth2ClientCredentials{config: config}
+}
+
+// Authenticate implements HTTPAuthenticator.
+func (o *OAuth2ClientCredentials) Authenticate(req *http.Request) error {
+	token, tokenType, err := o.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", tokenType+" "+token)
+	return nil
+}
+
+// Token returns a valid access token and its type, fetching a new one if
+// the cached token is missing or about to expire. Callers waiting for a
+// fetch return early when their context is done; the fetch itself isn't
+// tied to any one caller's context, so it still completes for the others.
+func (o *OAuth2ClientCredentials) Token(ctx context.Context) (token, tokenType string, err error) {
+	o.mu.Lock()
+	if o.token != "" && o.config.Clock().Before(o.refreshAt) {
+		defer o.mu.Unlock()
+		return o.token, o.tokenType, nil
+	}
+	f := o.inflight
+	if f == nil {
+		f = &tokenFetch{done: make(chan struct{})}
+		o.inflight = f
+		go o.refresh(context.WithoutCancel(ctx), f)
+	}
+	o.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.token, f.tokenType, f.err
+	case <-ctx.Done():
+		return "", "", fmt.Errorf("oauth2: waiting for token: %w", ctx.Err())
+	}
+}
+
+// refresh runs f and caches the token it fetched.
+func (o *OAuth2ClientCredentials) refresh(ctx context.Context, f *tokenFetch) {
+	var refreshAt time.Time
+	f.token, f.tokenType, refreshAt, f.err = o.fetch(ctx)
+
+	o.mu.Lock()
+	if f.err == nil {
+		o.token, o.tokenType, o.refreshAt = f.token, f.tokenType, refreshAt
+	}
+	o.inflight = nil
+	o.mu.Unlock()
+	close(f.done)
+}
+
+// Invalidate drops the cached token, e.g. after the service rejected it.
+func (o *OAuth2ClientCredentials) Invalidate() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token = ""
+}
+
+// fetch requests a token and returns it with the time to refresh it.
+func (o *OAuth2ClientCredentials) fetch(ctx context.Context) (token, tokenType string, refreshAt time.Time, err error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(o.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	for key, values := range o.config.EndpointParams {
+		form[key] = values
+	}
+	if o.config.AuthInParams {
+		form.Set("client_id", o.config.ClientID)
+		form.Set("client_secret", o.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !o.config.AuthInParams {
+		req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+	}
+
+	issuedAt := o.config.Clock()
+	resp, err := o.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: reading token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: decoding token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", "", time.Time{}, fmt.Errorf("oauth2: token response has no access_token")
+	}
+
+	tokenType = payload.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	// Tokens without expires_in are reused until the service rejects them
+	refreshAt = issuedAt.Add(100 * 365 * 24 * time.Hour)
+	if payload.ExpiresIn > 0 {
+		lifetime := time.Duration(payload.ExpiresIn) * time.Second
+		refreshAt = issuedAt.Add(lifetime - min(o.config.RefreshBefore, lifetime/2))
+	}
+	return payload.AccessToken, tokenType, refreshAt, nil
+}
+
+// authRoundTripper authenticates each request attempt.
+type authRoundTripper struct {
+	base http.RoundTripper
+	auth HTTPAuthenticator
+}
+
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	authed := req.Clone(req.Context())
+	if err := rt.auth.Authenticate(authed); err != nil {
+		return nil, err
+	}
+
+	resp, err := rt.base.RoundTrip(authed)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		// Don't keep sending a token the service no longer accepts
+		if inv, ok := rt.auth.(interface{ Invalidate() }); ok {
+			inv.Invalidate()
+		}
+	}
+	return resp, err
+}
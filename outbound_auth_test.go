@@ -0,0 +1,292 @@
+package volt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTokenServer serves client credentials tokens numbered by request.
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+			return
+		}
+		if r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "read write" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	newAuth := func(tokenURL string, clock *fakeClock) *OAuth2ClientCredentials {
+		return NewOAuth2ClientCredentials(OAuth2Config{
+			TokenURL:     tokenURL,
+			ClientID:     "client",
+			ClientSecret: "s3cret",
+			Scopes:       []string{"read", "write"},
+			Clock:        clock.Now,
+		})
+	}
+
+	t.Run("caches token until shortly before expiry", func(t *testing.T) {
+		server, issued := newTokenServer(t, 3600)
+		clock := newFakeClock()
+		auth := newAuth(server.URL, clock)
+
+		token, tokenType, err := auth.Token(ctx)
+		assertNil(t, err)
+		assertEqual(t, "token-1", token)
+		assertEqual(t, "Bearer", tokenType)
+
+		clock.Advance(58 * time.Minute)
+		token, _, _ = auth.Token(ctx)
+		assertEqual(t, "token-1", token)
+
+		clock.Advance(time.Minute)
+		token, _, _ = auth.Token(ctx)
+		assertEqual(t, "token-2", token)
+		assertEqual(t, int32(2), issued.Load())
+	})
+
+	t.Run("short-lived tokens refresh at half their lifetime", func(t *testing.T) {
+		server, _ := newTokenServer(t, 60)
+		clock := newFakeClock()
+		auth := newAuth(server.URL, clock)
+
+		auth.Token(ctx)
+		clock.Advance(30 * time.Second)
+		token, _, _ := auth.Token(ctx)
+		assertEqual(t, "token-2", token)
+	})
+
+	t.Run("concurrent callers share a fetch and keep their deadlines", func(t *testing.T) {
+		release := make(chan struct{})
+		var issued atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, issued.Add(1))
+		}))
+		defer server.Close()
+		auth := newAuth(server.URL, newFakeClock())
+
+		results := make(chan string, 3)
+		for range 3 {
+			go func() {
+				token, _, err := auth.Token(ctx)
+				if err != nil {
+					token = err.Error()
+				}
+				results <- token
+			}()
+		}
+
+		short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, _, err := auth.Token(short)
+		assertTrue(t, errors.Is(err, context.DeadlineExceeded))
+
+		close(release)
+		for range 3 {
+			assertEqual(t, "token-1", <-results)
+		}
+		assertEqual(t, int32(1), issued.Load())
+	})
+
+	t.Run("surfaces token endpoint errors", func(t *testing.T) {
+		server, _ := newTokenServer(t, 3600)
+		auth := NewOAuth2ClientCredentials(OAuth2Config{TokenURL: server.URL, ClientID: "client", ClientSecret: "wrong"})
+
+		_, _, err := auth.Token(ctx)
+		assertEqual(t, `oauth2: token endpoint returned 401: {"error":"invalid_client"}`, err.Error())
+	})
+
+	t.Run("authenticates service requests and refetches after 401", func(t *testing.T) {
+		tokenServer, issued := newTokenServer(t, 3600)
+
+		var rejectNext atomic.Bool
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rejectNext.Swap(false) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, r.Header.Get("Authorization"))
+		}))
+		defer api.Close()
+
+		app := newTestApp()
+		RegisterHTTPService(app, "api", func(c *http.Client) *http.Client { return c },
+			WithHTTPAuth(newAuth(tokenServer.URL, newFakeClock())),
+		)
+		assertNil(t, app.registry.Initialize(ctx, app))
+		client := app.registry.MustGet("api").(*http.Client)
+
+		get := func() (int, string) {
+			req, _ := http.NewRequest("GET", api.URL, nil)
+			resp, err := client.Do(req)
+			assertNil(t, err)
+			defer resp.Body.Close()
+			var body [64]byte
+			n, _ := resp.Body.Read(body[:])
+			assertEqual(t, "", req.Header.Get("Authorization"))
+			return resp.StatusCode, string(body[:n])
+		}
+
+		_, auth := get()
+		assertEqual(t, "Bearer token-1", auth)
+		_, auth = get()
+		assertEqual(t, "Bearer token-1", auth)
+
+		rejectNext.Store(true)
+		code, _ := get()
+		assertEqual(t, http.StatusUnauthorized, code)
+
+		_, auth = get()
+		assertEqual(t, "Bearer token-2", auth)
+		assertEqual(t, int32(2), issued.Load())
+	})
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	var current atomic.Value
+	current.Store("key-1")
+	auth := APIKeyAuth("X-API-Key", func(ctx context.Context) (string, error) {
+		return current.Load().(string), nil
+	})
+
+	req, _ := http.NewRequest("GET", "http://test", nil)
+	assertNil(t, auth.Authenticate(req))
+	assertEqual(t, "key-1", req.Header.Get("X-API-Key"))
+
+	current.Store("key-2")
+	assertNil(t, auth.Authenticate(req))
+	assertEqual(t, "key-2", req.Header.Get("X-API-Key"))
+
+	failing := APIKeyAuth("X-API-Key", func(ctx context.Context) (string, error) {
+		return "", errors.New("vault sealed")
+	})
+	assertEqual(t, "api key: vault sealed", failing.Authenticate(req).Error())
+}
+
+func TestHTTPServiceMutualTLS(t *testing.T) {
+	clientCert := newTestCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	register := func(opts ...HTTPServiceOption) *http.Client {
+		app := newTestApp()
+		RegisterHTTPService(app, "secure", func(c *http.Client) *http.Client { return c },
+			append([]HTTPServiceOption{WithHTTPRetries(0, 0, 0)}, opts...)...)
+		assertNil(t, app.registry.Initialize(context.Background(), app))
+		return app.registry.MustGet("secure").(*http.Client)
+	}
+
+	t.Run("presents client certificate", func(t *testing.T) {
+		client := register(WithHTTPRootCAs(rootCAs), WithHTTPClientCertificate(clientCert))
+
+		resp, err := client.Get(server.URL)
+		assertNil(t, err)
+		defer resp.Body.Close()
+		var body [64]byte
+		n, _ := resp.Body.Read(body[:])
+		assertEqual(t, "volt-test-client", string(body[:n]))
+	})
+
+	t.Run("applies to a custom transport", func(t *testing.T) {
+		transport := &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+		client := register(WithHTTPTransport(transport), WithHTTPRootCAs(rootCAs), WithHTTPClientCertificate(clientCert))
+
+		resp, err := client.Get(server.URL)
+		assertNil(t, err)
+		resp.Body.Close()
+		assertEqual(t, 0, len(transport.TLSClientConfig.Certificates))
+	})
+
+	t.Run("custom round trippers can't take TLS settings", func(t *testing.T) {
+		app := newTestApp()
+		RegisterHTTPService(app, "secure", func(c *http.Client) *http.Client { return c },
+			WithHTTPTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) { return nil, errors.New("unused") })),
+			WithHTTPClientCertificate(clientCert))
+
+		err := app.registry.Initialize(context.Background(), app)
+		assertTrue(t, err != nil && strings.Contains(err.Error(), "TLS settings need an *http.Transport"))
+	})
+
+	t.Run("rejects unknown server without custom CA", func(t *testing.T) {
+		client := register(WithHTTPClientCertificate(clientCert))
+
+		_, err := client.Get(server.URL)
+		assertTrue(t, err != nil)
+	})
+}
+
+// newTestCertificate creates a self-signed client certificate.
+func newTestCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "volt-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// roundTripFunc is a function adapter for http.RoundTripper.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
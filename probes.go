@@ -0,0 +1,136 @@
+package volt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// --- Probes ---
+
+// Probe identifies a Kubernetes-style probe endpoint.
+type Probe string
+
+const (
+	// ProbeLiveness fails only when the process must be restarted.
+	// It is unaffected by startup and shutdown.
+	ProbeLiveness Probe = "liveness"
+
+	// ProbeReadiness fails until the app has started, as soon as Shutdown
+	// begins, and whenever a critical readiness check fails.
+	ProbeReadiness Probe = "readiness"
+
+	// ProbeStartup fails until start hooks and service initialization
+	// have finished.
+	ProbeStartup Probe = "startup"
+)
+
+// probePaths maps each probe to its endpoint.
+var probePaths = []struct {
+	probe   Probe
+	path    string
+	summary string
+}{
+	{ProbeLiveness, "/livez", "Liveness probe"},
+	{ProbeReadiness, "/readyz", "Readiness probe"},
+	{ProbeStartup, "/startupz", "Startup probe"},
+}
+
+// RegisterProbes adds /livez, /readyz and /startupz endpoints tied to the
+// application lifecycle. Each check runs on the probes listed in its
+// HealthCheck.Probes; plain HealthCheckers and the automatic checks for
+// registered databases and HTTP services run on readiness only.
+//
+// Example:
+//
+//	volt.RegisterProbes(app,
+//	    volt.HealthCheck{
+//	        Name:     "migrations",
+//	        Critical: true,
+//	        Probes:   []volt.Probe{volt.ProbeStartup},
+//	        Func:     checkMigrations,
+//	    },
+//	)
+func RegisterProbes(app *App, checks ...HealthChecker) {
+	for _, p := range probePaths {
+		registerProbe(app, p.probe, p.path, p.summary, checks)
+	}
+}
+
+func registerProbe(app *App, probe Probe, path, summary string, checks []HealthChecker) {
+	runner := newHealthRunner(app.config.Health)
+
+	Register(app, Operation{
+		Method:      "GET",
+		Path:        path,
+		Summary:     summary,
+		Tags:        []string{"health"},
+		OperationID: string(probe) + "-probe",
+	}, func(ctx context.Context, input *HealthInput) (*HealthOutput, error) {
+		if status := app.probeGate(probe); status != "" {
+			out := &HealthOutput{Status: http.StatusServiceUnavailable}
+			out.Body.Status = status
+			out.Body.Version = app.config.Version
+			return out, nil
+		}
+
+		var selected []healthCheck
+		for i, check := range checks {
+			if probeIncludes(check, probe) {
+				selected = append(selected, newHealthCheck(fmt.Sprintf("check:%d", i), check))
+			}
+		}
+		for _, check := range app.registry.HealthChecks() {
+			if probeIncludes(check, probe) {
+				selected = append(selected, newHealthCheck("service:"+check.Name, check))
+			}
+		}
+
+		return runner.report(ctx, selected, app.config.Version), nil
+	})
+}
+
+// probeGate returns the status reported before any check runs, or "" if
+// the lifecycle allows the probe to run its checks.
+func (a *App) probeGate(probe Probe) string {
+	switch probe {
+	case ProbeStartup:
+		if !a.Started() {
+			return "starting"
+		}
+	case ProbeReadiness:
+		if a.draining.Load() {
+			return "draining"
+		}
+		if !a.Started() {
+			return "starting"
+		}
+	}
+	return ""
+}
+
+// startGate answers requests other than probes with 503 until Start has
+// completed, so Run can listen while start hooks and services initialize.
+func (a *App) startGate(next http.Handler) http.Handler {
+	probes := make(map[string]bool, len(probePaths))
+	for _, p := range probePaths {
+		probes[p.path] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.started.Load() || probes[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", "1")
+		a.writeProblem(w, r, CodeServiceUnavailable.New("service is starting"))
+	})
+}
+
+// probeIncludes reports whether checker runs on probe.
+func probeIncludes(checker HealthChecker, probe Probe) bool {
+	if hc, ok := checker.(HealthCheck); ok && len(hc.Probes) > 0 {
+		return slices.Contains(hc.Probes, probe)
+	}
+	return probe == ProbeReadiness
+}
@@ -0,0 +1,200 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterProbes(t *testing.T) {
+	probe := func(t *testing.T, app *App, path string) (int, string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		var body struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec.Code, body.Status
+	}
+
+	t.Run("startup and readiness wait for Start", func(t *testing.T) {
+		app := newTestApp()
+		RegisterProbes(app)
+
+		var duringHook int
+		app.OnStart(func(ctx context.Context) error {
+			duringHook, _ = probe(t, app, "/startupz")
+			return nil
+		})
+
+		code, status := probe(t, app, "/startupz")
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		assertEqual(t, "starting", status)
+		code, _ = probe(t, app, "/readyz")
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		code, _ = probe(t, app, "/livez")
+		assertEqual(t, http.StatusOK, code)
+
+		assertNil(t, app.Start(context.Background()))
+		assertEqual(t, http.StatusServiceUnavailable, duringHook)
+		assertTrue(t, app.Ready())
+
+		for _, path := range []string{"/livez", "/readyz", "/startupz"} {
+			code, status = probe(t, app, path)
+			assertEqual(t, http.StatusOK, code)
+			assertEqual(t, "healthy", status)
+		}
+	})
+
+	t.Run("other routes wait for Start when served by Run", func(t *testing.T) {
+		app := newTestApp()
+		RegisterProbes(app)
+		RegisterSimple(app, "GET", "/orders", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		handler := app.startGate(app.Router())
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/orders", nil))
+		assertEqual(t, http.StatusServiceUnavailable, rec.Code)
+		assertEqual(t, "1", rec.Header().Get("Retry-After"))
+		assertTrue(t, strings.Contains(rec.Body.String(), `"code":"SERVICE_UNAVAILABLE"`))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/startupz", nil))
+		assertEqual(t, http.StatusServiceUnavailable, rec.Code)
+		assertTrue(t, strings.Contains(rec.Body.String(), `"status":"starting"`))
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/livez", nil))
+		assertEqual(t, http.StatusOK, rec.Code)
+
+		assertNil(t, app.Start(context.Background()))
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/orders", nil))
+		assertEqual(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("readiness fails once Shutdown begins", func(t *testing.T) {
+		app := newTestApp()
+		RegisterProbes(app)
+		assertNil(t, app.Start(context.Background()))
+		assertNil(t, app.Shutdown(context.Background()))
+
+		code, status := probe(t, app, "/readyz")
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		assertEqual(t, "draining", status)
+		code, _ = probe(t, app, "/livez")
+		assertEqual(t, http.StatusOK, code)
+	})
+
+	t.Run("checks run only on tagged probes", func(t *testing.T) {
+		app := newTestApp()
+		down := func(ctx context.Context) error { return errors.New("down") }
+		RegisterProbes(app,
+			HealthCheck{Name: "deadlock", Critical: true, Probes: []Probe{ProbeLiveness}, Func: down},
+			HealthCheck{Name: "cache", Critical: true, Func: down},
+		)
+		assertNil(t, app.Start(context.Background()))
+
+		code, _ := probe(t, app, "/livez")
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		code, _ = probe(t, app, "/readyz")
+		assertEqual(t, http.StatusServiceUnavailable, code)
+		code, _ = probe(t, app, "/startupz")
+		assertEqual(t, http.StatusOK, code)
+	})
+
+	t.Run("registered databases gate readiness only", func(t *testing.T) {
+		app := newTestApp()
+		config := DefaultDatabaseConfig()
+		config.Driver = "volt-fake"
+		RegisterDatabase(app, "primary", config)
+		RegisterProbes(app)
+		assertNil(t, app.Start(context.Background()))
+		defer app.Shutdown(context.Background())
+
+		code, _ := probe(t, app, "/readyz")
+		assertEqual(t, http.StatusOK, code)
+	})
+}
+
+func TestShutdownDrain(t *testing.T) {
+	app := newTestApp(WithDrainDelay(200 * time.Millisecond))
+	RegisterProbes(app)
+	assertNil(t, app.Start(context.Background()))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assertNil(t, err)
+	app.server = &http.Server{Handler: app.router}
+	go app.server.Serve(ln)
+
+	done := make(chan struct{})
+	go func() {
+		app.Shutdown(context.Background())
+		close(done)
+	}()
+
+	// The server keeps serving during the drain delay, reporting not ready
+	deadline := time.Now().Add(150 * time.Millisecond)
+	code := 0
+	for code != http.StatusServiceUnavailable && time.Now().Before(deadline) {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/readyz")
+		assertNil(t, err)
+		resp.Body.Close()
+		code = resp.StatusCode
+	}
+	assertEqual(t, http.StatusServiceUnavailable, code)
+
+	<-done
+	_, err = http.Get("http://" + ln.Addr().String() + "/readyz")
+	assertTrue(t, err != nil)
+}
+
+func TestRunFailures(t *testing.T) {
+	newApp := func(port int) (*App, *bool) {
+		app := newTestApp(WithHost("127.0.0.1"), WithPort(port))
+		released := new(bool)
+		app.Registry().Register("cache", struct{}{}, func(ctx context.Context) error {
+			*released = true
+			return nil
+		})
+		return app, released
+	}
+
+	t.Run("bind failures return before start hooks", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assertNil(t, err)
+		defer ln.Close()
+
+		app, released := newApp(ln.Addr().(*net.TCPAddr).Port)
+		hookRan := false
+		app.OnStart(func(ctx context.Context) error {
+			hookRan = true
+			return nil
+		})
+
+		err = app.Run()
+		assertTrue(t, err != nil && strings.Contains(err.Error(), "listen on 127.0.0.1:"))
+		assertTrue(t, !hookRan)
+		assertTrue(t, *released)
+	})
+
+	t.Run("start failures release services", func(t *testing.T) {
+		app, released := newApp(0)
+		app.OnStart(func(ctx context.Context) error { return errors.New("migrations failed") })
+
+		err := app.Run()
+		assertTrue(t, err != nil && strings.Contains(err.Error(), "migrations failed"))
+		assertTrue(t, *released)
+	})
+}
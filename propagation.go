@@ -0,0 +1,81 @@
+package volt
+
+import (
+	"context"
+	"net/http"
+)
+
+// --- Header Propagation ---
+
+// WithHTTPPropagateHeaders forwards the named headers of the inbound request
+// to this service, e.g. "X-Tenant-ID", "Accept-Language" or "Authorization".
+// Only listed headers are forwarded, so credentials never reach services
+// they weren't meant for. Outbound requests must carry the handler's context
+// (http.NewRequestWithContext).
+func WithHTTPPropagateHeaders(headers ...string) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.PropagateHeaders = append(c.PropagateHeaders, headers...)
+	}
+}
+
+// WithHTTPRequestIDHeader sets the header carrying the inbound request ID
+// (default: "X-Request-Id"). An empty name disables request ID propagation.
+func WithHTTPRequestIDHeader(name string) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.RequestIDHeader = name
+	}
+}
+
+// inboundHeadersMiddleware makes the inbound request headers available to
+// outbound calls made with the request context.
+func inboundHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), inboundHeadersKey, r.Header)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// inboundHeaders returns the headers of the request being served, if any.
+func inboundHeaders(ctx context.Context) http.Header {
+	h, _ := ctx.Value(inboundHeadersKey).(http.Header)
+	return h
+}
+
+// propagationRoundTripper copies the request ID and allowlisted inbound
+// headers onto outbound requests. Headers set explicitly on the outbound
+// request take precedence.
+type propagationRoundTripper struct {
+	base            http.RoundTripper
+	requestIDHeader string
+	headers         []string
+}
+
+func (rt *propagationRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	inbound := inboundHeaders(ctx)
+	requestID := RequestID(ctx)
+
+	var out *http.Request
+	set := func(name string, values []string) {
+		if len(values) == 0 || req.Header.Get(name) != "" {
+			return
+		}
+		if out == nil {
+			// RoundTrippers must not modify the caller's request
+			out = req.Clone(ctx)
+		}
+		out.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+
+	if rt.requestIDHeader != "" && requestID != "" {
+		set(rt.requestIDHeader, []string{requestID})
+	}
+	for _, name := range rt.headers {
+		set(name, inbound.Values(name))
+	}
+
+	if out == nil {
+		return rt.base.RoundTrip(req)
+	}
+	return rt.base.RoundTrip(out)
+}
@@ -0,0 +1,100 @@
+package volt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHeaderPropagation(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]http.Header{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+	}))
+	defer upstream.Close()
+
+	app := newTestApp()
+	identity := func(c *http.Client) *http.Client { return c }
+	RegisterHTTPService(app, "partner", identity, WithHTTPPropagateHeaders("X-Tenant-ID"))
+	RegisterHTTPService(app, "internal", identity, WithHTTPPropagateHeaders("X-Tenant-ID", "Authorization"))
+	RegisterHTTPService(app, "anonymous", identity, WithHTTPRequestIDHeader(""))
+	assertNil(t, app.registry.Initialize(context.Background(), app))
+
+	Register(app, Operation{Method: "GET", Path: "/fanout"}, func(ctx context.Context, in *EmptyInput) (*StatusOutput, error) {
+		for _, name := range []string{"partner", "internal", "anonymous"} {
+			req, _ := http.NewRequestWithContext(ctx, "GET", upstream.URL+"/"+name, nil)
+			if name == "partner" {
+				req.Header.Set("X-Tenant-ID", "explicit")
+			}
+			resp, err := Use[*http.Client](ctx, name).Do(req)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body.Close()
+		}
+		return &StatusOutput{}, nil
+	})
+
+	req := httptest.NewRequest("GET", "/fanout", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.Header.Set("Authorization", "Bearer user-token")
+	rec := httptest.NewRecorder()
+	app.Router().ServeHTTP(rec, req)
+	assertEqual(t, http.StatusOK, rec.Code)
+
+	t.Run("forwards request ID by default", func(t *testing.T) {
+		assertEqual(t, "req-123", received["/partner"].Get("X-Request-Id"))
+		assertEqual(t, "req-123", received["/internal"].Get("X-Request-Id"))
+		assertEqual(t, "", received["/anonymous"].Get("X-Request-Id"))
+	})
+
+	t.Run("forwards only allowlisted headers", func(t *testing.T) {
+		assertEqual(t, "acme", received["/internal"].Get("X-Tenant-ID"))
+		assertEqual(t, "Bearer user-token", received["/internal"].Get("Authorization"))
+		assertEqual(t, "", received["/partner"].Get("Authorization"))
+		assertEqual(t, "", received["/anonymous"].Get("X-Tenant-ID"))
+	})
+
+	t.Run("explicit outbound headers win", func(t *testing.T) {
+		assertEqual(t, "explicit", received["/partner"].Get("X-Tenant-ID"))
+	})
+}
+
+func TestPropagationRoundTripper(t *testing.T) {
+	t.Run("does not modify the caller's request", func(t *testing.T) {
+		rt := &propagationRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				assertEqual(t, "id-1", req.Header.Get("X-Request-Id"))
+				return &http.Response{StatusCode: 200}, nil
+			}},
+			requestIDHeader: "X-Request-Id",
+		}
+
+		req, _ := http.NewRequestWithContext(WithRequestID(context.Background(), "id-1"), "GET", "http://test", nil)
+		_, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, "", req.Header.Get("X-Request-Id"))
+	})
+
+	t.Run("passes requests without inbound context through", func(t *testing.T) {
+		rt := &propagationRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				assertEqual(t, 0, len(req.Header))
+				return &http.Response{StatusCode: 200}, nil
+			}},
+			requestIDHeader: "X-Request-Id",
+			headers:         []string{"X-Tenant-ID"},
+		}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		_, err := rt.RoundTrip(req)
+		assertNil(t, err)
+	})
+}
@@ -0,0 +1,551 @@
+package volt
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// --- Rate Limiting Middleware ---
+
+// RateLimitConfig configures rate limiting.
+type RateLimitConfig struct {
+	// Requests per window
+	Requests int
+
+	// Time window
+	Window time.Duration
+
+	// Key extractor (default: IP address)
+	KeyFunc func(r *http.Request) string
+
+	// Store for rate limit state (default: in-memory)
+	Store RateLimitStore
+
+	// Context-aware store, e.g. a shared Redis store. Takes precedence over Store.
+	ContextStore ContextRateLimitStore
+
+	// What to do when ContextStore returns an error (default: RateLimitFailOpen)
+	FailureMode RateLimitFailureMode
+}
+
+// RateLimitStore interface for rate limit state storage.
+type RateLimitStore interface {
+	// Allow checks if request is allowed and increments counter
+	Allow(key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time)
+}
+
+// ContextRateLimitStore is a context-aware rate limit store for backends that
+// can fail, such as a store shared between replicas over the network.
+type ContextRateLimitStore interface {
+	// Allow checks if request is allowed and increments counter.
+	// A non-nil error means no decision could be made.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, remaining int, resetAt time.Time, err error)
+}
+
+// ContextStore adapts a RateLimitStore to ContextRateLimitStore.
+func ContextStore(store RateLimitStore) ContextRateLimitStore {
+	return contextStoreAdapter{store}
+}
+
+type contextStoreAdapter struct {
+	store RateLimitStore
+}
+
+func (a contextStoreAdapter) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	allowed, remaining, resetAt := a.store.Allow(key, limit, window)
+	return allowed, remaining, resetAt, nil
+}
+
+// RateLimitFailureMode decides how requests are handled when the rate limit
+// store is unavailable.
+type RateLimitFailureMode int
+
+const (
+	// RateLimitFailOpen lets requests through unlimited while the store is down.
+	RateLimitFailOpen RateLimitFailureMode = iota
+
+	// RateLimitFailClosed rejects requests with 503 while the store is down.
+	RateLimitFailClosed
+)
+
+// resolveRateLimitStore picks the store to use from a config.
+func resolveRateLimitStore(ctxStore ContextRateLimitStore, store RateLimitStore, fallback func() RateLimitStore) ContextRateLimitStore {
+	if ctxStore != nil {
+		return ctxStore
+	}
+	if store == nil {
+		store = fallback()
+	}
+	return ContextStore(store)
+}
+
+// RateLimit creates a rate limiting middleware.
+func RateLimit(config RateLimitConfig) func(http.Handler) http.Handler {
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(r *http.Request) string {
+			return r.RemoteAddr
+		}
+	}
+	// The default store sweeps during Allow, as nothing would stop a
+	// background sweeper
+	store := resolveRateLimitStore(config.ContextStore, config.Store, func() RateLimitStore {
+		return &InMemoryRateLimitStore{sweepEvery: time.Minute}
+	})
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := config.KeyFunc(r)
+			allowed, remaining, resetAt, err := store.Allow(r.Context(), key, config.Requests, config.Window)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limit store unavailable", "error", err)
+				if config.FailureMode == RateLimitFailClosed {
+					http.Error(w, "rate limiting unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writeRateLimitHeaders(w.Header().Set, config.Requests, remaining, resetAt, allowed)
+
+			if !allowed {
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRateLimitHeaders sets the X-RateLimit-* headers, and Retry-After when
+// the request was rejected.
+func writeRateLimitHeaders(set func(name, value string), limit, remaining int, resetAt time.Time, allowed bool) {
+	set("X-RateLimit-Limit", strconv.Itoa(limit))
+	set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+	if !allowed {
+		set("Retry-After", strconv.Itoa(retryAfterSeconds(time.Until(resetAt))))
+	}
+}
+
+// retryAfterSeconds rounds a wait duration up to whole seconds, never less than one.
+func retryAfterSeconds(d time.Duration) int {
+	secs := int(math.Ceil(d.Seconds()))
+	if secs < 1 {
+		return 1
+	}
+	return secs
+}
+
+// --- Per-Operation Rate Limits ---
+
+// OperationRateLimit declares a rate limit for a single operation.
+// Attach it with WithRateLimit; Volt enforces it as Huma middleware and
+// documents it in the OpenAPI spec.
+type OperationRateLimit struct {
+	// Requests per window
+	Requests int
+
+	// Time window
+	Window time.Duration
+
+	// Key identifies who the limit applies to (default: RateLimitByIP)
+	Key RateLimitKey
+
+	// Store for rate limit state (default: the app's shared in-memory store)
+	Store RateLimitStore
+
+	// Context-aware store, e.g. a shared Redis store. Takes precedence over Store.
+	ContextStore ContextRateLimitStore
+
+	// What to do when ContextStore returns an error (default: RateLimitFailOpen)
+	FailureMode RateLimitFailureMode
+}
+
+// RateLimitKey derives the rate limit bucket for a request.
+type RateLimitKey struct {
+	// Name describes the key in the OpenAPI spec (e.g. "ip", "user", "param:id")
+	Name string
+
+	// Func returns the bucket key for the request
+	Func func(ctx huma.Context) string
+}
+
+// RateLimitByIP keys requests by client IP address.
+func RateLimitByIP() RateLimitKey {
+	return RateLimitKey{Name: "ip", Func: clientIP}
+}
+
+// RateLimitByParam keys requests by the value of a path parameter,
+// e.g. to limit writes per resource.
+func RateLimitByParam(name string) RateLimitKey {
+	return RateLimitKey{
+		Name: "param:" + name,
+		Func: func(ctx huma.Context) string {
+			return ctx.Param(name)
+		},
+	}
+}
+
+// RateLimitByUser keys requests by the authenticated user stored with
+// WithUser. Unauthenticated requests fall back to the client IP.
+//
+// Example:
+//
+//	volt.RateLimitByUser(func(u *User) string { return u.ID.String() })
+func RateLimitByUser[T any](id func(user T) string) RateLimitKey {
+	return RateLimitKey{
+		Name: "user",
+		Func: func(ctx huma.Context) string {
+			if user, ok := User[T](ctx.Context()); ok {
+				return "user:" + id(user)
+			}
+			return "ip:" + clientIP(ctx)
+		},
+	}
+}
+
+// clientIP returns the client address without its port.
+func clientIP(ctx huma.Context) string {
+	addr := ctx.RemoteAddr()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// WithRateLimit creates an Operation with a per-operation rate limit.
+// Convenience function for cleaner operation definitions.
+func WithRateLimit(op Operation, limit OperationRateLimit) Operation {
+	if op.Metadata == nil {
+		op.Metadata = make(map[string]any)
+	}
+	op.Metadata["ratelimit"] = limit
+	return op
+}
+
+// documentRateLimit adds the x-ratelimit extension and 429 response to an operation.
+func documentRateLimit(op *huma.Operation, limit OperationRateLimit) {
+	key := limit.Key.Name
+	if key == "" {
+		key = "ip"
+	}
+
+	if op.Extensions == nil {
+		op.Extensions = make(map[string]any)
+	}
+	op.Extensions["x-ratelimit"] = map[string]any{
+		"requests":       limit.Requests,
+		"window_seconds": limit.Window.Seconds(),
+		"key":            key,
+	}
+	op.Errors = append(op.Errors, http.StatusTooManyRequests)
+}
+
+// rateLimitMiddleware creates the Huma middleware that enforces
+// per-operation rate limits declared with WithRateLimit.
+func (a *App) rateLimitMiddleware() func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		limit, ok := ctx.Operation().Metadata["ratelimit"].(OperationRateLimit)
+		if !ok {
+			next(ctx)
+			return
+		}
+
+		keyFunc := limit.Key.Func
+		if keyFunc == nil {
+			keyFunc = clientIP
+		}
+		store := resolveRateLimitStore(limit.ContextStore, limit.Store, a.defaultRateLimitStore)
+
+		// Scope buckets to the operation so limits don't bleed across endpoints
+		op := ctx.Operation()
+		key := op.Method + " " + op.Path + "|" + keyFunc(ctx)
+
+		allowed, remaining, resetAt, err := store.Allow(ctx.Context(), key, limit.Requests, limit.Window)
+		if err != nil {
+			a.logger.Warn("rate limit store unavailable", "operation", op.OperationID, "error", err)
+			if limit.FailureMode == RateLimitFailClosed {
+				a.writeError(ctx, ErrServiceUnavailable("rate limiting unavailable"))
+				return
+			}
+			next(ctx)
+			return
+		}
+		writeRateLimitHeaders(ctx.SetHeader, limit.Requests, remaining, resetAt, allowed)
+
+		if !allowed {
+			a.writeError(ctx, ErrTooManyRequests(""))
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// defaultRateLimitStore returns the app's shared in-memory store, creating it
+// on first use.
+func (a *App) defaultRateLimitStore() RateLimitStore {
+	a.rateLimitOnce.Do(func() {
+		a.rateLimitStore.Store(NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{}))
+	})
+	return a.rateLimitStore.Load()
+}
+
+// --- In-Memory Store ---
+
+// InMemoryRateLimitStoreConfig configures an InMemoryRateLimitStore.
+type InMemoryRateLimitStoreConfig struct {
+	// Maximum number of tracked keys. Once reached, the least recently
+	// used key is evicted to make room (default: 100000)
+	MaxKeys int
+
+	// How often idle keys are swept in the background (default: 1 minute).
+	// A negative value disables the background sweeper.
+	CleanupInterval time.Duration
+
+	// Time source (default: time.Now). Override in tests.
+	Clock func() time.Time
+}
+
+// InMemoryRateLimitStore is a sliding window rate limit store for a single
+// process. Each key keeps the request count of the current and previous fixed
+// window, and the previous count is weighted by how much of it still overlaps
+// the sliding window. This gives smooth limits with constant memory per key.
+//
+// The zero value is usable but does not sweep idle keys in the background;
+// use NewInMemoryRateLimitStore for that.
+type InMemoryRateLimitStore struct {
+	maxKeys int
+	clock   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front = most recently used
+
+	stop      chan struct{}
+	closeOnce sync.Once
+
+	// Sweep interval for stores without a background sweeper; swept
+	// during Allow (0: never)
+	sweepEvery time.Duration
+	lastSweep  time.Time
+}
+
+type rateLimitEntry struct {
+	key         string
+	window      time.Duration
+	windowStart time.Time
+	prevCount   int
+	currCount   int
+	lastSeen    time.Time
+}
+
+// NewInMemoryRateLimitStore creates an in-memory store and starts its
+// background sweeper. Call Close to stop the sweeper.
+func NewInMemoryRateLimitStore(config InMemoryRateLimitStoreConfig) *InMemoryRateLimitStore {
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = time.Minute
+	}
+
+	s := &InMemoryRateLimitStore{
+		maxKeys: config.MaxKeys,
+		clock:   config.Clock,
+	}
+	s.init()
+
+	if config.CleanupInterval > 0 {
+		s.stop = make(chan struct{})
+		go s.sweepLoop(config.CleanupInterval)
+	}
+
+	return s
+}
+
+// init lazily sets defaults so the zero value is usable. Caller holds mu or
+// has exclusive access.
+func (s *InMemoryRateLimitStore) init() {
+	if s.entries == nil {
+		s.entries = make(map[string]*list.Element)
+		s.lru = list.New()
+	}
+	if s.maxKeys <= 0 {
+		s.maxKeys = 100000
+	}
+	if s.clock == nil {
+		s.clock = time.Now
+	}
+}
+
+// Allow implements RateLimitStore.
+func (s *InMemoryRateLimitStore) Allow(key string, limit int, window time.Duration) (bool, int, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.init()
+	now := s.clock()
+	if s.sweepEvery > 0 && now.Sub(s.lastSweep) >= s.sweepEvery {
+		s.sweep(now)
+		s.lastSweep = now
+	}
+
+	// A non-positive window cannot be enforced
+	if window <= 0 {
+		return true, limit, now
+	}
+
+	e := s.entry(key, window, now)
+	e.lastSeen = now
+	e.advance(now)
+
+	// The tolerance absorbs float rounding at the exact time nextAllowed reports
+	used := e.estimate(now)
+	if used+1 > float64(limit)+1e-9 {
+		return false, 0, e.nextAllowed(limit)
+	}
+
+	e.currCount++
+	remaining := limit - int(math.Ceil(used)) - 1
+	if remaining < 0 {
+		remaining = 0
+	}
+	return true, remaining, e.windowStart.Add(window)
+}
+
+// entry returns the entry for key, creating it (and evicting the least
+// recently used key if at capacity) when needed. Caller holds mu.
+func (s *InMemoryRateLimitStore) entry(key string, window time.Duration, now time.Time) *rateLimitEntry {
+	if el, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(el)
+		e := el.Value.(*rateLimitEntry)
+		if e.window != window {
+			// Limit was reconfigured for this key - start over
+			*e = rateLimitEntry{key: key, window: window, windowStart: now.Truncate(window)}
+		}
+		return e
+	}
+
+	for len(s.entries) >= s.maxKeys {
+		s.removeElement(s.lru.Back())
+	}
+
+	e := &rateLimitEntry{key: key, window: window, windowStart: now.Truncate(window)}
+	s.entries[key] = s.lru.PushFront(e)
+	return e
+}
+
+// advance rolls the fixed windows forward to the one containing now.
+func (e *rateLimitEntry) advance(now time.Time) {
+	elapsed := now.Sub(e.windowStart)
+	if elapsed < e.window {
+		return
+	}
+	if elapsed < 2*e.window {
+		e.prevCount = e.currCount
+	} else {
+		e.prevCount = 0
+	}
+	e.currCount = 0
+	e.windowStart = now.Truncate(e.window)
+}
+
+// estimate returns the weighted request count over the sliding window ending at now.
+func (e *rateLimitEntry) estimate(now time.Time) float64 {
+	overlap := 1 - float64(now.Sub(e.windowStart))/float64(e.window)
+	return float64(e.prevCount)*overlap + float64(e.currCount)
+}
+
+// nextAllowed returns the earliest time at which one more request fits.
+func (e *rateLimitEntry) nextAllowed(limit int) time.Time {
+	if limit <= 0 {
+		return e.windowStart.Add(e.window)
+	}
+
+	// Room in the current window once enough of the previous one slides out
+	free := float64(limit - e.currCount - 1)
+	if free >= 0 && e.prevCount > 0 {
+		return e.windowStart.Add(ceilDuration(float64(e.window) * (1 - free/float64(e.prevCount))))
+	}
+
+	// Current window is full: wait until it becomes the previous window and
+	// has partially slid out
+	next := e.windowStart.Add(e.window)
+	return next.Add(ceilDuration(float64(e.window) * (1 - float64(limit-1)/float64(e.currCount))))
+}
+
+func ceilDuration(ns float64) time.Duration {
+	return time.Duration(math.Ceil(ns))
+}
+
+// Len returns the number of tracked keys.
+func (s *InMemoryRateLimitStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Sweep removes keys that have been idle for at least two windows, since
+// they no longer carry any state that affects a decision.
+func (s *InMemoryRateLimitStore) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.init()
+	s.sweep(s.clock())
+}
+
+// sweep removes keys idle at now. Caller holds mu.
+func (s *InMemoryRateLimitStore) sweep(now time.Time) {
+	// Windows differ per key, so every entry is checked
+	for el := s.lru.Back(); el != nil; {
+		e := el.Value.(*rateLimitEntry)
+		if now.Sub(e.lastSeen) < 2*e.window {
+			el = el.Prev()
+			continue
+		}
+		prev := el.Prev()
+		s.removeElement(el)
+		el = prev
+	}
+}
+
+// removeElement drops an entry. Caller holds mu.
+func (s *InMemoryRateLimitStore) removeElement(el *list.Element) {
+	e := s.lru.Remove(el).(*rateLimitEntry)
+	delete(s.entries, e.key)
+}
+
+func (s *InMemoryRateLimitStore) sweepLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Sweep()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background sweeper. The store remains usable.
+func (s *InMemoryRateLimitStore) Close() error {
+	s.closeOnce.Do(func() {
+		if s.stop != nil {
+			close(s.stop)
+		}
+	})
+	return nil
+}
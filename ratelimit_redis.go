@@ -0,0 +1,338 @@
+package volt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Redis Rate Limit Store ---
+
+// RedisRateLimitStoreConfig configures a RedisRateLimitStore.
+type RedisRateLimitStoreConfig struct {
+	// Server address, e.g. "localhost:6379"
+	Addr string
+
+	// Optional AUTH credentials (Username requires Redis 6+)
+	Username string
+	Password string
+
+	// Database number selected on connect
+	DB int
+
+	// Prefix for all keys written by the store (default: "volt:ratelimit:")
+	KeyPrefix string
+
+	// Maximum number of idle connections kept for reuse (default: 10)
+	PoolSize int
+
+	// Dial and per-command timeouts, used when the context has no deadline
+	// (default: 1 second each)
+	DialTimeout time.Duration
+	Timeout     time.Duration
+
+	// Time source used to align windows (default: time.Now). Override in tests.
+	Clock func() time.Time
+}
+
+// RedisRateLimitStore is a ContextRateLimitStore shared between replicas.
+// It speaks the Redis protocol directly, so it works with Redis, Valkey,
+// KeyDB, Dragonfly and other compatible servers without extra dependencies.
+//
+// It uses the same sliding window algorithm as InMemoryRateLimitStore, with
+// one counter per fixed window. The check and increment run in a single Lua
+// script, so concurrent requests across replicas never over-admit.
+type RedisRateLimitStore struct {
+	config RedisRateLimitStoreConfig
+	pool   chan *redisConn
+}
+
+// slidingWindowScript admits a request if the weighted count stays within
+// the limit. All arithmetic is integral (milliseconds) to avoid rounding.
+//
+//	KEYS[1] current window counter
+//	KEYS[2] previous window counter
+//	ARGV[1] limit, ARGV[2] window ms, ARGV[3] elapsed ms into current window
+//
+// Returns {allowed (0|1), current count, previous count}.
+const slidingWindowScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+if prev * (window - elapsed) + (curr + 1) * window > limit * window then
+  return {0, curr, prev}
+end
+curr = redis.call('INCR', KEYS[1])
+if curr == 1 then
+  redis.call('PEXPIRE', KEYS[1], window * 2)
+end
+return {1, curr, prev}
+`
+
+// NewRedisRateLimitStore creates a Redis-backed rate limit store.
+// Connections are dialed lazily on first use.
+func NewRedisRateLimitStore(config RedisRateLimitStoreConfig) *RedisRateLimitStore {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "volt:ratelimit:"
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 10
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Second
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+
+	return &RedisRateLimitStore{
+		config: config,
+		pool:   make(chan *redisConn, config.PoolSize),
+	}
+}
+
+// Allow implements ContextRateLimitStore.
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	now := s.config.Clock()
+	if window <= 0 {
+		return true, limit, now, nil
+	}
+
+	windowMs := window.Milliseconds()
+	if windowMs <= 0 {
+		return false, 0, now, fmt.Errorf("redis rate limit: window %v is below 1ms", window)
+	}
+
+	windowStart := now.Truncate(window)
+	elapsedMs := now.Sub(windowStart).Milliseconds()
+	// Both keys share a hash tag so EVAL works on Redis Cluster. The tag is
+	// a hash of the key, as keys may contain braces themselves (e.g.
+	// "POST /items/{id}|1.2.3.4"); the key follows it for readability.
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	base := fmt.Sprintf("%s{%016x}:%s:", s.config.KeyPrefix, hash.Sum64(), key)
+	currKey := base + strconv.FormatInt(windowStart.UnixMilli(), 10)
+	prevKey := base + strconv.FormatInt(windowStart.Add(-window).UnixMilli(), 10)
+
+	reply, err := s.do(ctx, "EVAL", slidingWindowScript, "2", currKey, prevKey,
+		strconv.Itoa(limit), strconv.FormatInt(windowMs, 10), strconv.FormatInt(elapsedMs, 10))
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("redis rate limit: unexpected reply %v", reply)
+	}
+	var n [3]int64
+	for i, v := range values {
+		if n[i], ok = v.(int64); !ok {
+			return false, 0, time.Time{}, fmt.Errorf("redis rate limit: unexpected reply %v", reply)
+		}
+	}
+
+	e := rateLimitEntry{
+		window:      window,
+		windowStart: windowStart,
+		currCount:   int(n[1]),
+		prevCount:   int(n[2]),
+	}
+
+	if n[0] == 0 {
+		return false, 0, e.nextAllowed(limit), nil
+	}
+
+	remaining := limit - int(math.Ceil(e.estimate(now)))
+	if remaining < 0 {
+		remaining = 0
+	}
+	return true, remaining, windowStart.Add(window), nil
+}
+
+// Ping checks connectivity to the server.
+func (s *RedisRateLimitStore) Ping(ctx context.Context) error {
+	_, err := s.do(ctx, "PING")
+	return err
+}
+
+// Close closes all idle connections.
+func (s *RedisRateLimitStore) Close() error {
+	for {
+		select {
+		case c := <-s.pool:
+			c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs a single command on a pooled connection.
+func (s *RedisRateLimitStore) do(ctx context.Context, args ...string) (any, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(ctx, s.config.Timeout, args...)
+	if err != nil {
+		var serverErr redisError
+		if !errors.As(err, &serverErr) {
+			// Connection state is unknown after an I/O error
+			c.conn.Close()
+			return nil, err
+		}
+	}
+	s.put(c)
+	return reply, err
+}
+
+func (s *RedisRateLimitStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.pool:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: s.config.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis rate limit: dial: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	if s.config.Password != "" {
+		args := []string{"AUTH", s.config.Password}
+		if s.config.Username != "" {
+			args = []string{"AUTH", s.config.Username, s.config.Password}
+		}
+		if _, err := c.do(ctx, s.config.Timeout, args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis rate limit: auth: %w", err)
+		}
+	}
+	if s.config.DB != 0 {
+		if _, err := c.do(ctx, s.config.Timeout, "SELECT", strconv.Itoa(s.config.DB)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis rate limit: select: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+func (s *RedisRateLimitStore) put(c *redisConn) {
+	select {
+	case s.pool <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// --- Minimal RESP Client ---
+
+// redisError is an error reply from the server. The connection stays usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// do writes a command and reads its reply.
+func (c *redisConn) do(ctx context.Context, timeout time.Duration, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.conn.Write(encodeRESPCommand(args)); err != nil {
+		return nil, err
+	}
+	return readRESP(c.r)
+}
+
+// encodeRESPCommand encodes a command as a RESP array of bulk strings.
+func encodeRESPCommand(args []string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return []byte(b.String())
+}
+
+// readRESP reads a single RESP2 reply. Simple and bulk strings are returned
+// as string, integers as int64, arrays as []any and nil replies as nil.
+// Error replies are returned as redisError.
+func readRESP(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		values := make([]any, n)
+		for i := range values {
+			v, err := readRESP(r)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,311 @@
+package volt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is an in-process RESP server that emulates the commands used by
+// RedisRateLimitStore. EVAL runs a Go port of slidingWindowScript.
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	counters map[string]int64
+	evalKeys [][2]string
+	commands []string
+	conns    []net.Conn
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeRedis{ln: ln, password: password, counters: make(map[string]int64)}
+	go f.serve()
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeRedis) Addr() string {
+	return f.ln.Addr().String()
+}
+
+// Close stops the server and drops all connections, simulating an outage.
+func (f *fakeRedis) Close() {
+	f.ln.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.conns {
+		c.Close()
+	}
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.conns = append(f.conns, conn)
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		v, err := readRESP(r)
+		if err != nil {
+			return
+		}
+		raw, _ := v.([]any)
+		args := make([]string, len(raw))
+		for i, a := range raw {
+			args[i], _ = a.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		f.mu.Unlock()
+
+		var reply string
+		switch {
+		case cmd == "AUTH":
+			if args[len(args)-1] == f.password {
+				authed = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case cmd == "PING":
+			reply = "+PONG\r\n"
+		case cmd == "SELECT":
+			reply = "+OK\r\n"
+		case cmd == "EVAL":
+			reply = f.eval(args[3], args[4], args[5], args[6], args[7])
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) eval(currKey, prevKey, limitArg, windowArg, elapsedArg string) string {
+	limit, _ := strconv.ParseInt(limitArg, 10, 64)
+	window, _ := strconv.ParseInt(windowArg, 10, 64)
+	elapsed, _ := strconv.ParseInt(elapsedArg, 10, 64)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.evalKeys = append(f.evalKeys, [2]string{currKey, prevKey})
+	curr, prev := f.counters[currKey], f.counters[prevKey]
+	allowed := 0
+	if prev*(window-elapsed)+(curr+1)*window <= limit*window {
+		curr++
+		f.counters[currKey] = curr
+		allowed = 1
+	}
+	return fmt.Sprintf("*3\r\n:%d\r\n:%d\r\n:%d\r\n", allowed, curr, prev)
+}
+
+func (f *fakeRedis) Commands() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("enforces limit through the server", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		clock := newFakeClock()
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Clock: clock.Now})
+		defer store.Close()
+
+		for i := 0; i < 3; i++ {
+			allowed, remaining, _, err := store.Allow(ctx, "k", 3, time.Minute)
+			assertNil(t, err)
+			assertTrue(t, allowed)
+			assertEqual(t, 2-i, remaining)
+		}
+
+		allowed, remaining, resetAt, err := store.Allow(ctx, "k", 3, time.Minute)
+		assertNil(t, err)
+		assertTrue(t, !allowed)
+		assertEqual(t, 0, remaining)
+		assertTrue(t, resetAt.After(clock.Now()))
+	})
+
+	t.Run("keys share a hash tag for Redis Cluster", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		clock := newFakeClock()
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Clock: clock.Now})
+		defer store.Close()
+
+		key := "POST /items/{id}|1.2.3.4"
+		_, _, _, err := store.Allow(ctx, key, 3, time.Minute)
+		assertNil(t, err)
+
+		// Redis Cluster hashes the text between the first "{" and the
+		// next "}"
+		hashTag := func(k string) string {
+			start := strings.Index(k, "{")
+			end := strings.Index(k[start+1:], "}")
+			assertTrue(t, start >= 0 && end > 0)
+			return k[start+1 : start+1+end]
+		}
+
+		_, _, _, err = store.Allow(ctx, "POST /items/{id}|5.6.7.8", 3, time.Minute)
+		assertNil(t, err)
+
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		assertEqual(t, 2, len(server.evalKeys))
+		curr, prev := server.evalKeys[0][0], server.evalKeys[0][1]
+		tag := hashTag(curr)
+		assertEqual(t, tag, hashTag(prev))
+		assertEqual(t, 16, len(tag))
+		assertTrue(t, !strings.ContainsAny(tag, "{}|/ "))
+		assertEqual(t, "volt:ratelimit:{"+tag+"}:"+key+":"+strconv.FormatInt(clock.Now().UnixMilli(), 10), curr)
+		assertTrue(t, hashTag(server.evalKeys[1][0]) != tag)
+	})
+
+	t.Run("shares state between store instances", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		clock := newFakeClock()
+		a := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Clock: clock.Now})
+		b := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Clock: clock.Now})
+		defer a.Close()
+		defer b.Close()
+
+		allowed, _, _, err := a.Allow(ctx, "k", 1, time.Minute)
+		assertNil(t, err)
+		assertTrue(t, allowed)
+
+		allowed, _, _, err = b.Allow(ctx, "k", 1, time.Minute)
+		assertNil(t, err)
+		assertTrue(t, !allowed)
+	})
+
+	t.Run("weights previous window", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		clock := newFakeClock()
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Clock: clock.Now})
+		defer store.Close()
+
+		for i := 0; i < 10; i++ {
+			store.Allow(ctx, "k", 10, time.Minute)
+		}
+
+		clock.Advance(65 * time.Second)
+		allowed, _, resetAt, err := store.Allow(ctx, "k", 10, time.Minute)
+		assertNil(t, err)
+		assertTrue(t, !allowed)
+
+		clock.Advance(resetAt.Sub(clock.Now()))
+		allowed, _, _, err = store.Allow(ctx, "k", 10, time.Minute)
+		assertNil(t, err)
+		assertTrue(t, allowed)
+	})
+
+	t.Run("authenticates and selects database on connect", func(t *testing.T) {
+		server := newFakeRedis(t, "secret")
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), Password: "secret", DB: 2})
+		defer store.Close()
+
+		assertNil(t, store.Ping(ctx))
+		assertEqual(t, "AUTH,SELECT,PING", strings.Join(server.Commands(), ","))
+	})
+
+	t.Run("reuses pooled connections", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr()})
+		defer store.Close()
+
+		for i := 0; i < 5; i++ {
+			assertNil(t, store.Ping(ctx))
+		}
+
+		server.mu.Lock()
+		conns := len(server.conns)
+		server.mu.Unlock()
+		assertEqual(t, 1, conns)
+	})
+
+	t.Run("returns error when server is down", func(t *testing.T) {
+		server := newFakeRedis(t, "")
+		store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr()})
+		defer store.Close()
+
+		assertNil(t, store.Ping(ctx))
+		server.Close()
+
+		_, _, _, err := store.Allow(ctx, "k", 1, time.Minute)
+		assertTrue(t, err != nil)
+	})
+}
+
+func TestRateLimitFailureMode(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store := NewRedisRateLimitStore(RedisRateLimitStoreConfig{Addr: server.Addr(), DialTimeout: 50 * time.Millisecond})
+	defer store.Close()
+	server.Close()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name string
+		mode RateLimitFailureMode
+		want int
+	}{
+		{name: "fail open lets requests through", mode: RateLimitFailOpen, want: http.StatusOK},
+		{name: "fail closed rejects requests", mode: RateLimitFailClosed, want: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RateLimit(RateLimitConfig{
+				Requests:     1,
+				Window:       time.Minute,
+				ContextStore: store,
+				FailureMode:  tt.mode,
+			})(ok)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			assertEqual(t, tt.want, rec.Code)
+		})
+	}
+}
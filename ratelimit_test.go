@@ -0,0 +1,346 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced time source for deterministic tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestInMemoryRateLimitStore(t *testing.T) {
+	t.Run("zero value allows requests", func(t *testing.T) {
+		store := &InMemoryRateLimitStore{}
+
+		allowed, remaining, _ := store.Allow("test-key", 10, time.Minute)
+
+		assertTrue(t, allowed)
+		assertEqual(t, 9, remaining)
+	})
+
+	t.Run("rejects once limit is reached", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		for i := 0; i < 3; i++ {
+			allowed, remaining, _ := store.Allow("k", 3, time.Minute)
+			assertTrue(t, allowed)
+			assertEqual(t, 2-i, remaining)
+		}
+
+		allowed, remaining, resetAt := store.Allow("k", 3, time.Minute)
+		assertTrue(t, !allowed)
+		assertEqual(t, 0, remaining)
+		assertTrue(t, resetAt.After(clock.Now()))
+	})
+
+	t.Run("keys are limited independently", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		allowed, _, _ := store.Allow("a", 1, time.Minute)
+		assertTrue(t, allowed)
+		allowed, _, _ = store.Allow("a", 1, time.Minute)
+		assertTrue(t, !allowed)
+		allowed, _, _ = store.Allow("b", 1, time.Minute)
+		assertTrue(t, allowed)
+	})
+
+	t.Run("previous window is weighted by overlap", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		for i := 0; i < 10; i++ {
+			store.Allow("k", 10, time.Minute)
+		}
+
+		// Shortly into the next window most of the previous one still counts
+		clock.Advance(65 * time.Second)
+		allowed, _, resetAt := store.Allow("k", 10, time.Minute)
+		assertTrue(t, !allowed)
+		assertTrue(t, resetAt.After(clock.Now()))
+
+		// Waiting until the reported reset time admits the next request
+		clock.Advance(resetAt.Sub(clock.Now()))
+		allowed, _, _ = store.Allow("k", 10, time.Minute)
+		assertTrue(t, allowed)
+	})
+
+	t.Run("state expires after two idle windows", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		store.Allow("k", 1, time.Minute)
+		clock.Advance(2 * time.Minute)
+
+		allowed, remaining, _ := store.Allow("k", 1, time.Minute)
+		assertTrue(t, allowed)
+		assertEqual(t, 0, remaining)
+	})
+
+	t.Run("evicts least recently used key at capacity", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, MaxKeys: 2, CleanupInterval: -1})
+
+		store.Allow("a", 1, time.Minute)
+		store.Allow("b", 1, time.Minute)
+		store.Allow("c", 1, time.Minute)
+
+		assertEqual(t, 2, store.Len())
+
+		// "a" was evicted, so it starts fresh
+		allowed, _, _ := store.Allow("a", 1, time.Minute)
+		assertTrue(t, allowed)
+	})
+
+	t.Run("Sweep removes idle keys only", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		store.Allow("idle", 5, time.Minute)
+		clock.Advance(90 * time.Second)
+		store.Allow("active", 5, time.Minute)
+		clock.Advance(45 * time.Second)
+
+		store.Sweep()
+
+		assertEqual(t, 1, store.Len())
+	})
+
+	t.Run("background sweeper evicts idle keys", func(t *testing.T) {
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{CleanupInterval: 5 * time.Millisecond})
+		defer store.Close()
+
+		store.Allow("k", 5, time.Millisecond)
+
+		deadline := time.Now().Add(time.Second)
+		for store.Len() > 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		assertEqual(t, 0, store.Len())
+	})
+
+	t.Run("stores without a sweeper sweep during Allow", func(t *testing.T) {
+		clock := newFakeClock()
+		store := &InMemoryRateLimitStore{clock: clock.Now, sweepEvery: time.Minute}
+
+		store.Allow("idle", 5, time.Second)
+		clock.Advance(time.Minute)
+		store.Allow("active", 5, time.Second)
+
+		assertEqual(t, 1, store.Len())
+	})
+
+	t.Run("is safe under concurrent use", func(t *testing.T) {
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{CleanupInterval: time.Millisecond})
+		defer store.Close()
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		allowedCount := 0
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					if allowed, _, _ := store.Allow("shared", 100, time.Hour); allowed {
+						mu.Lock()
+						allowedCount++
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		assertEqual(t, 100, allowedCount)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("sets integer headers and rejects over limit", func(t *testing.T) {
+		clock := newFakeClock()
+		store := NewInMemoryRateLimitStore(InMemoryRateLimitStoreConfig{Clock: clock.Now, CleanupInterval: -1})
+
+		handler := RateLimit(RateLimitConfig{
+			Requests: 2,
+			Window:   time.Minute,
+			Store:    store,
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		var rec *httptest.ResponseRecorder
+		for i := 0; i < 3; i++ {
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			if i < 2 {
+				assertEqual(t, http.StatusOK, rec.Code)
+				assertEqual(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+				assertEqual(t, fmt.Sprint(1-i), rec.Header().Get("X-RateLimit-Remaining"))
+				assertEqual(t, "", rec.Header().Get("Retry-After"))
+			}
+		}
+
+		assertEqual(t, http.StatusTooManyRequests, rec.Code)
+		assertEqual(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+
+		reset, err := strconv.ParseInt(rec.Header().Get("X-RateLimit-Reset"), 10, 64)
+		assertNil(t, err)
+		assertTrue(t, reset > clock.Now().Unix())
+
+		retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+		assertNil(t, err)
+		assertTrue(t, retryAfter >= 1)
+	})
+}
+
+func TestOperationRateLimit(t *testing.T) {
+	type itemInput struct {
+		ID string `path:"id"`
+	}
+
+	ok := func(ctx context.Context, in *itemInput) (*StatusOutput, error) {
+		out := &StatusOutput{}
+		out.Body.Status = "ok"
+		return out, nil
+	}
+
+	newApp := func() *App {
+		app := newTestApp()
+		Register(app, WithRateLimit(Operation{
+			Method:      "POST",
+			Path:        "/items/{id}",
+			OperationID: "update-item",
+		}, OperationRateLimit{Requests: 1, Window: time.Minute, Key: RateLimitByParam("id")}), ok)
+		Register(app, Operation{
+			Method:      "GET",
+			Path:        "/items/{id}",
+			OperationID: "get-item",
+		}, ok)
+		return app
+	}
+
+	do := func(app *App, method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		app.Router().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	t.Run("enforces limit per key", func(t *testing.T) {
+		app := newApp()
+		defer app.Shutdown(context.Background())
+
+		assertEqual(t, http.StatusOK, do(app, "POST", "/items/a").Code)
+
+		rec := do(app, "POST", "/items/a")
+		assertEqual(t, http.StatusTooManyRequests, rec.Code)
+		assertEqual(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+		assertTrue(t, rec.Header().Get("Retry-After") != "")
+		assertEqual(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		var problem ErrorModel
+		assertNil(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assertEqual(t, "RATE_LIMITED", problem.Code)
+		assertEqual(t, "/items/a", problem.Instance)
+
+		assertEqual(t, http.StatusOK, do(app, "POST", "/items/b").Code)
+	})
+
+	t.Run("does not limit other operations", func(t *testing.T) {
+		app := newApp()
+		defer app.Shutdown(context.Background())
+
+		do(app, "POST", "/items/a")
+		do(app, "POST", "/items/a")
+
+		rec := do(app, "GET", "/items/a")
+		assertEqual(t, http.StatusOK, rec.Code)
+		assertEqual(t, "", rec.Header().Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("documents limit in OpenAPI", func(t *testing.T) {
+		app := newApp()
+		op := app.API().OpenAPI().Paths["/items/{id}"].Post
+
+		ext, ok := op.Extensions["x-ratelimit"].(map[string]any)
+		assertTrue(t, ok)
+		assertEqual(t, 1, ext["requests"].(int))
+		assertEqual(t, 60.0, ext["window_seconds"].(float64))
+		assertEqual(t, "param:id", ext["key"].(string))
+		assertNotNil(t, op.Responses["429"])
+	})
+
+	t.Run("RateLimitByUser falls back to IP", func(t *testing.T) {
+		type user struct{ ID string }
+		app := newTestApp()
+		Register(app, WithRateLimit(Operation{
+			Method: "GET",
+			Path:   "/me",
+		}, OperationRateLimit{
+			Requests: 1,
+			Window:   time.Minute,
+			Key:      RateLimitByUser(func(u *user) string { return u.ID }),
+		}), func(ctx context.Context, in *EmptyInput) (*StatusOutput, error) {
+			return &StatusOutput{}, nil
+		})
+
+		withUser := func(id string) *http.Request {
+			req := httptest.NewRequest("GET", "/me", nil)
+			return req.WithContext(WithUser(req.Context(), &user{ID: id}))
+		}
+
+		for _, tc := range []struct {
+			req  *http.Request
+			want int
+		}{
+			{withUser("alice"), http.StatusOK},
+			{withUser("alice"), http.StatusTooManyRequests},
+			{withUser("bob"), http.StatusOK},
+			{httptest.NewRequest("GET", "/me", nil), http.StatusOK},
+			{httptest.NewRequest("GET", "/me", nil), http.StatusTooManyRequests},
+		} {
+			rec := httptest.NewRecorder()
+			app.Router().ServeHTTP(rec, tc.req)
+			assertEqual(t, tc.want, rec.Code)
+		}
+	})
+	t.Run("shutdown can overlap the first request", func(t *testing.T) {
+		app := newApp()
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/items/1", nil))
+		}()
+		assertNil(t, app.Shutdown(context.Background()))
+		wg.Wait()
+	})
+}
@@ -0,0 +1,147 @@
+package volt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humachi"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// --- Panic Recovery ---
+
+// recoverer recovers panics outside operations, such as in router
+// middleware or handlers registered with RegisterSimple, and responds like
+// other errors. Panics in operations and their Huma middleware are
+// recovered by the operation and recoverMiddleware, so they show up in its
+// metrics.
+func (a *App) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := a.recoverPanic(r.Context(), "", rec)
+			if r.Header.Get("Connection") == "Upgrade" {
+				err.Record(r.Context())
+				return
+			}
+			a.writeProblem(w, r, err)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// recoverMiddleware recovers panics in the Huma middleware of operations,
+// e.g. in an AuthzPolicy, and responds like the operation would.
+func (a *App) recoverMiddleware(ctx huma.Context, next func(huma.Context)) {
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		if rec == http.ErrAbortHandler {
+			panic(rec)
+		}
+		a.writeError(ctx, a.recoverPanic(ctx.Context(), ctx.Operation().OperationID, rec))
+	}()
+
+	next(ctx)
+}
+
+// writeProblem writes err as problem details from outside Huma, e.g. from
+// router middleware.
+func (a *App) writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	if a.errorCatalogPath != "" {
+		ctx = context.WithValue(ctx, errorCatalogKey, a.errorCatalogPath)
+	}
+	var model *ErrorModel
+	if !errors.As(problemFromError(ctx, err), &model) {
+		return
+	}
+	if model.Instance == "" {
+		model.Instance = r.URL.Path
+	}
+
+	// Write through Huma, so the body links its schema like other problems
+	op := &huma.Operation{Method: r.Method, Path: r.URL.Path}
+	_ = huma.WriteErr(a.api, humachi.NewContext(op, r, w), model.Status, "", model)
+}
+
+// recoverPanic logs and counts a recovered panic and returns the 500 error
+// to respond with. The error's stack is where the panic happened, so it is
+// recorded on the span when the error is.
+func (a *App) recoverPanic(ctx context.Context, operation string, rec any) *Error {
+	var cause error
+	if err, ok := rec.(error); ok {
+		cause = fmt.Errorf("panic: %w", err)
+	} else {
+		cause = fmt.Errorf("panic: %v", rec)
+	}
+	e := &Error{
+		status:  http.StatusInternalServerError,
+		code:    CodeInternal.Code,
+		message: "internal server error",
+		cause:   cause,
+		stack:   panicCallers(),
+	}
+
+	var attrs []any
+	if operation != "" {
+		attrs = append(attrs, "operation", operation)
+	}
+	attrs = append(attrs, "panic", fmt.Sprint(rec), "request_id", middleware.GetReqID(ctx))
+	if traceID := TraceID(ctx); traceID != "" {
+		attrs = append(attrs, "trace_id", traceID)
+	}
+	attrs = append(attrs, "stack", e.StackTrace())
+	a.logger.ErrorContext(ctx, "panic recovered", attrs...)
+
+	if a.panics != nil {
+		var opts []metric.AddOption
+		if operation != "" {
+			opts = append(opts, metric.WithAttributes(attrOperationID.String(operation)))
+		}
+		a.panics.Add(ctx, 1, opts...)
+	}
+	return e
+}
+
+// newPanicCounter creates the counter of recovered panics.
+func (a *App) newPanicCounter() metric.Int64Counter {
+	panics, err := a.otel.Meter("volt").Int64Counter("http.server.panics",
+		metric.WithDescription("Panics recovered while serving requests"),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		a.logger.Error("failed to create panic counter", "error", err)
+		return nil
+	}
+	return panics
+}
+
+// panicCallers captures the stack of a panic from a deferred recover,
+// starting at the function that panicked.
+func panicCallers() []uintptr {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(1, pcs)
+	pcs = pcs[:n]
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			return pcs[i+1:]
+		}
+	}
+	return pcs
+}
@@ -0,0 +1,170 @@
+package volt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecoverer(t *testing.T) {
+	app, mem := newInMemoryApp(t)
+
+	Register(app, Operation{Method: "GET", Path: "/boom", OperationID: "boom"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+		panic("something broke")
+	})
+	Register(app, Operation{Method: "GET", Path: "/detached", OperationID: "detached"}, func(ctx context.Context, in *struct{}) (*struct{}, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		Use[*http.Client](ctx, "payments")
+		return nil, nil
+	})
+	RegisterSimple(app, "GET", "/raw", func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]int
+		m["boom"]++
+	})
+	RegisterSimple(app, "GET", "/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+	Register(app, WithAuthz(Operation{Method: "GET", Path: "/guarded", OperationID: "guarded"}, AuthzPermission("admin")),
+		func(ctx context.Context, in *struct{}) (*struct{}, error) { return nil, nil })
+	SetAuthzPolicy(app, AuthzPolicyFunc(func(ctx context.Context, req AuthzRequest, requirement AuthzRequirement) error {
+		panic("policy broke")
+	}))
+
+	get := func(path string) (*httptest.ResponseRecorder, ErrorModel) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Request-Id", "req-"+strings.TrimPrefix(path, "/"))
+		app.Router().ServeHTTP(rec, req)
+		var model ErrorModel
+		if err := json.Unmarshal(rec.Body.Bytes(), &model); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+		return rec, model
+	}
+
+	t.Run("operation panics respond with problem details", func(t *testing.T) {
+		rec, model := get("/boom")
+		assertEqual(t, http.StatusInternalServerError, rec.Code)
+		assertEqual(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		assertEqual(t, "INTERNAL_ERROR", model.Code)
+		assertEqual(t, "internal server error", model.Detail)
+		assertEqual(t, "/boom", model.Instance)
+		assertEqual(t, 32, len(model.TraceID))
+		assertTrue(t, !strings.Contains(rec.Body.String(), "something broke"))
+	})
+
+	t.Run("recorded as a span exception with the panic's stack", func(t *testing.T) {
+		span, ok := mem.Span(SpanNamed("GET /boom"))
+		assertTrue(t, ok)
+		assertEqual(t, codes.Error, span.Status.Code)
+
+		stack := exceptionStack(span)
+		assertTrue(t, strings.HasPrefix(stack, "github.com/bermos/volt.TestRecoverer.func1"))
+		assertTrue(t, strings.Contains(stack, "recover_test.go"))
+	})
+
+	t.Run("logged with request ID and stack", func(t *testing.T) {
+		logs := mem.Logs("panic recovered")
+		assertEqual(t, 1, len(logs))
+		assertEqual(t, "something broke", logs[0].Attr("panic"))
+		assertEqual(t, "boom", logs[0].Attr("operation"))
+		assertEqual(t, "req-boom", logs[0].Attr("request_id"))
+		assertTrue(t, strings.Contains(logs[0].Attr("stack"), "recover_test.go"))
+		assertTrue(t, logs[0].TraceID.IsValid())
+		assertEqual(t, 0, len(mem.Logs("request failed")))
+	})
+
+	t.Run("counted per operation", func(t *testing.T) {
+		points := mem.DataPoints("http.server.panics", attribute.String("operation.id", "boom"))
+		assertEqual(t, 1, len(points))
+		assertEqual(t, 1.0, points[0].Value)
+
+		failed := mem.DataPoints("http.server.operation.errors", attribute.String("operation.id", "boom"))
+		assertEqual(t, 1, len(failed))
+	})
+
+	t.Run("Use misuse is reported clearly", func(t *testing.T) {
+		rec, model := get("/detached")
+		assertEqual(t, http.StatusInternalServerError, rec.Code)
+		assertEqual(t, "INTERNAL_ERROR", model.Code)
+
+		logs := mem.Logs("panic recovered")
+		assertEqual(t, `volt.Use[*http.Client]("payments"): called with non-Volt context; pass the handler's context itself, not one derived from it`,
+			logs[len(logs)-1].Attr("panic"))
+	})
+
+	t.Run("panics in operation middleware", func(t *testing.T) {
+		rec, model := get("/guarded")
+		assertEqual(t, http.StatusInternalServerError, rec.Code)
+		assertEqual(t, "INTERNAL_ERROR", model.Code)
+		assertEqual(t, "/guarded", model.Instance)
+		assertTrue(t, strings.Contains(rec.Body.String(), `"$schema":`))
+
+		logs := mem.Logs("panic recovered")
+		assertEqual(t, "guarded", logs[len(logs)-1].Attr("operation"))
+
+		points := mem.DataPoints("http.server.panics", attribute.String("operation.id", "guarded"))
+		assertEqual(t, 1, len(points))
+		failed := mem.DataPoints("http.server.operation.errors", attribute.String("operation.id", "guarded"))
+		assertEqual(t, 1, len(failed))
+	})
+
+	t.Run("panics outside operations", func(t *testing.T) {
+		mem.Reset()
+		rec, model := get("/raw")
+		assertEqual(t, http.StatusInternalServerError, rec.Code)
+		assertEqual(t, "application/problem+json", rec.Header().Get("Content-Type"))
+		assertEqual(t, "/raw", model.Instance)
+		assertEqual(t, 32, len(model.TraceID))
+		assertTrue(t, strings.Contains(rec.Body.String(), `"$schema":`))
+
+		logs := mem.Logs("panic recovered")
+		assertEqual(t, 1, len(logs))
+		assertEqual(t, "assignment to entry in nil map", logs[0].Attr("panic"))
+		assertEqual(t, "req-raw", logs[0].Attr("request_id"))
+
+		completed := mem.Logs("request completed")
+		assertEqual(t, 1, len(completed))
+		assertEqual(t, "500", completed[0].Attr("status"))
+
+		span, ok := mem.Span(SpanNamed("http.request"))
+		assertTrue(t, ok)
+		assertTrue(t, strings.Contains(exceptionStack(span), "github.com/bermos/volt.TestRecoverer.func3"))
+
+		var unattributed float64
+		for _, point := range mem.DataPoints("http.server.panics") {
+			if point.Attributes.Len() == 0 {
+				unattributed = point.Value
+			}
+		}
+		assertEqual(t, 1.0, unattributed)
+	})
+
+	t.Run("http.ErrAbortHandler is not recovered", func(t *testing.T) {
+		defer func() {
+			assertTrue(t, recover() == http.ErrAbortHandler)
+		}()
+		app.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+		t.Error("expected panic")
+	})
+}
+
+// exceptionStack returns the stack trace of the span's exception event.
+func exceptionStack(span tracetest.SpanStub) string {
+	for _, event := range span.Events {
+		for _, attr := range event.Attributes {
+			if attr.Key == "exception.stacktrace" {
+				return attr.Value.AsString()
+			}
+		}
+	}
+	return ""
+}
@@ -2,13 +2,18 @@ package volt
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Registry manages all registered services and their lifecycle.
@@ -20,6 +25,9 @@ type Registry struct {
 	// Factories for lazy initialization
 	httpServices map[string]*httpServiceFactory
 	dbServices   map[string]*dbServiceFactory
+
+	// Replacements installed with Override, e.g. fakes in tests
+	overrides map[string]any
 }
 
 type serviceEntry struct {
@@ -35,6 +43,8 @@ type httpServiceFactory struct {
 	config     HTTPServiceConfig
 	instance   any
 	httpClient *http.Client
+	breaker    *circuitBreaker
+	metrics    metric.Registration
 }
 
 type dbServiceFactory struct {
@@ -43,6 +53,7 @@ type dbServiceFactory struct {
 	factory  func(db *sql.DB) any
 	instance any
 	db       *sql.DB
+	metrics  metric.Registration
 }
 
 // NewRegistry creates a new service registry.
@@ -51,6 +62,7 @@ func NewRegistry() *Registry {
 		services:     make(map[string]*serviceEntry),
 		httpServices: make(map[string]*httpServiceFactory),
 		dbServices:   make(map[string]*dbServiceFactory),
+		overrides:    make(map[string]any),
 	}
 }
 
@@ -72,6 +84,9 @@ func (r *Registry) Get(name string) (any, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	if instance, ok := r.overrides[name]; ok {
+		return instance, true
+	}
 	if entry, ok := r.services[name]; ok {
 		return entry.instance, true
 	}
@@ -84,6 +99,16 @@ func (r *Registry) Get(name string) (any, bool) {
 	return nil, false
 }
 
+// Override replaces the named service with instance, typically a fake in
+// tests. Overridden HTTP services and databases are not initialized, so
+// they make no connections and are left out of health checks; call
+// Override before Initialize for those.
+func (r *Registry) Override(name string, instance any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[name] = instance
+}
+
 // MustGet retrieves a service or panics if not found.
 func (r *Registry) MustGet(name string) any {
 	svc, ok := r.Get(name)
@@ -100,7 +125,22 @@ func (r *Registry) Initialize(ctx context.Context, app *App) error {
 
 	// Initialize HTTP services
 	for name, factory := range r.httpServices {
-		client := r.createInstrumentedHTTPClient(app, factory.config, name)
+		if _, ok := r.overrides[name]; ok {
+			continue
+		}
+		if factory.config.CircuitBreaker != nil {
+			factory.breaker = newCircuitBreaker(name, *factory.config.CircuitBreaker)
+			metrics, err := factory.breaker.registerMetrics(app.otel.Meter("volt"))
+			if err != nil {
+				app.logger.Warn("failed to register circuit breaker metrics", "name", name, "error", err)
+			}
+			factory.metrics = metrics
+		}
+
+		client, err := r.createInstrumentedHTTPClient(app, factory)
+		if err != nil {
+			return fmt.Errorf("failed to initialize HTTP service %q: %w", name, err)
+		}
 		factory.httpClient = client
 		factory.instance = factory.factory(client)
 
@@ -109,12 +149,21 @@ func (r *Registry) Initialize(ctx context.Context, app *App) error {
 
 	// Initialize database services
 	for name, factory := range r.dbServices {
+		if _, ok := r.overrides[name]; ok {
+			continue
+		}
 		db, err := r.createInstrumentedDB(ctx, app, factory.config, name)
 		if err != nil {
 			return fmt.Errorf("failed to initialize database %q: %w", name, err)
 		}
 		factory.db = db
 
+		metrics, err := registerDBStatsMetrics(db, app.otel.Meter("volt"), name)
+		if err != nil {
+			app.logger.Warn("failed to register database metrics", "name", name, "error", err)
+		}
+		factory.metrics = metrics
+
 		if factory.factory != nil {
 			factory.instance = factory.factory(db)
 		} else {
@@ -144,8 +193,22 @@ func (r *Registry) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Stop reporting HTTP service metrics
+	for name, factory := range r.httpServices {
+		if factory.metrics != nil {
+			if err := factory.metrics.Unregister(); err != nil {
+				errs = append(errs, fmt.Errorf("unregister HTTP service metrics %q: %w", name, err))
+			}
+		}
+	}
+
 	// Close database connections
 	for name, factory := range r.dbServices {
+		if factory.metrics != nil {
+			if err := factory.metrics.Unregister(); err != nil {
+				errs = append(errs, fmt.Errorf("unregister database metrics %q: %w", name, err))
+			}
+		}
 		if factory.db != nil {
 			if err := factory.db.Close(); err != nil {
 				errs = append(errs, fmt.Errorf("close database %q: %w", name, err))
@@ -167,10 +230,14 @@ type HTTPServiceConfig struct {
 	Timeout time.Duration
 
 	// Retry configuration
-	MaxRetries     int
-	RetryWaitMin   time.Duration
-	RetryWaitMax   time.Duration
-	RetryOnStatus  []int // HTTP status codes to retry on
+	MaxRetries    int
+	RetryWaitMin  time.Duration
+	RetryWaitMax  time.Duration
+	RetryOnStatus []int // HTTP status codes to retry on
+
+	// Also retry non-idempotent methods such as POST and PATCH. Only enable
+	// this for services that deduplicate requests.
+	RetryNonIdempotent bool
 
 	// Custom transport options
 	MaxIdleConns        int
@@ -179,6 +246,39 @@ type HTTPServiceConfig struct {
 
 	// Headers to add to all requests
 	DefaultHeaders map[string]string
+
+	// Optional health probe: GET BaseURL+HealthPath must return 2xx.
+	// Non-critical unless HealthCritical is set.
+	HealthPath     string
+	HealthCritical bool
+	HealthTimeout  time.Duration // default: Config.Health.Timeout
+
+	// Optional circuit breaker; open breakers fail the service's health check
+	CircuitBreaker *CircuitBreakerConfig
+
+	// Outbound request budget and concurrency cap (0 = unlimited).
+	// Burst defaults to RequestsPerSecond rounded up.
+	RequestsPerSecond float64
+	Burst             int
+	MaxInFlight       int
+
+	// Outbound authentication, applied to every attempt
+	Auth HTTPAuthenticator
+
+	// TLS settings such as client certificates and custom root CAs
+	TLSConfig *tls.Config
+
+	// Replaces the network transport (default: an *http.Transport built
+	// from the settings above). Instrumentation, retries and the other
+	// layers still wrap it. TLSConfig is applied to a copy of it, which
+	// requires an *http.Transport.
+	Transport http.RoundTripper
+
+	// Header carrying the inbound request ID ("" disables)
+	RequestIDHeader string
+
+	// Inbound request headers forwarded to this service
+	PropagateHeaders []string
 }
 
 // DefaultHTTPServiceConfig returns sensible defaults.
@@ -188,10 +288,11 @@ func DefaultHTTPServiceConfig() HTTPServiceConfig {
 		MaxRetries:          3,
 		RetryWaitMin:        100 * time.Millisecond,
 		RetryWaitMax:        2 * time.Second,
-		RetryOnStatus:       []int{502, 503, 504},
+		RetryOnStatus:       []int{429, 502, 503, 504},
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     90 * time.Second,
+		RequestIDHeader:     "X-Request-Id",
 	}
 }
 
@@ -225,7 +326,8 @@ func RegisterHTTPService[T any](app *App, name string, factory func(client *http
 // HTTPServiceOption configures an HTTP service.
 type HTTPServiceOption func(*HTTPServiceConfig)
 
-// WithHTTPBaseURL sets the base URL for the service.
+// WithHTTPBaseURL sets the base URL for the service. Requests with a
+// path-only URL (e.g. "/users/42") are sent relative to it.
 func WithHTTPBaseURL(url string) HTTPServiceOption {
 	return func(c *HTTPServiceConfig) {
 		c.BaseURL = url
@@ -248,6 +350,14 @@ func WithHTTPRetries(max int, minWait, maxWait time.Duration) HTTPServiceOption
 	}
 }
 
+// WithHTTPRetryNonIdempotent allows retrying non-idempotent requests.
+// Requests whose body can't be replayed are never retried.
+func WithHTTPRetryNonIdempotent() HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.RetryNonIdempotent = true
+	}
+}
+
 // WithHTTPHeaders sets default headers.
 func WithHTTPHeaders(headers map[string]string) HTTPServiceOption {
 	return func(c *HTTPServiceConfig) {
@@ -255,12 +365,28 @@ func WithHTTPHeaders(headers map[string]string) HTTPServiceOption {
 	}
 }
 
+// WithHTTPTransport replaces the network transport of the service.
+func WithHTTPTransport(rt http.RoundTripper) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.Transport = rt
+	}
+}
+
+// WithHTTPHealthCheck probes path on the service from the health endpoint.
+// A critical probe failure makes the endpoint report unhealthy (503).
+func WithHTTPHealthCheck(path string, critical bool) HTTPServiceOption {
+	return func(c *HTTPServiceConfig) {
+		c.HealthPath = path
+		c.HealthCritical = critical
+	}
+}
+
 // createInstrumentedHTTPClient creates an HTTP client with OTEL instrumentation.
-func (r *Registry) createInstrumentedHTTPClient(app *App, config HTTPServiceConfig, name string) *http.Client {
-	transport := &http.Transport{
-		MaxIdleConns:        config.MaxIdleConns,
-		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
-		IdleConnTimeout:     config.IdleConnTimeout,
+func (r *Registry) createInstrumentedHTTPClient(app *App, factory *httpServiceFactory) (*http.Client, error) {
+	config, name := factory.config, factory.name
+	transport, err := httpTransport(config)
+	if err != nil {
+		return nil, err
 	}
 
 	// Wrap with OTEL instrumentation if available
@@ -272,14 +398,53 @@ func (r *Registry) createInstrumentedHTTPClient(app *App, config HTTPServiceConf
 		)
 	}
 
+	// Enforce the outbound budget outside the OTEL span so time spent
+	// queueing isn't reported as request latency
+	if config.RequestsPerSecond > 0 || config.MaxInFlight > 0 {
+		limiter := &limitRoundTripper{
+			base:  rt,
+			name:  name,
+			attrs: []attribute.KeyValue{attribute.String("service", name)},
+		}
+		if config.RequestsPerSecond > 0 {
+			limiter.bucket = newTokenBucket(config.RequestsPerSecond, config.Burst, nil)
+		}
+		if config.MaxInFlight > 0 {
+			limiter.slots = make(chan struct{}, config.MaxInFlight)
+		}
+		limiter.queued, _ = app.otel.Meter("volt").Float64Histogram("http.client.queue.duration",
+			metric.WithDescription("Time outbound HTTP requests waited for the rate limit and concurrency cap"),
+			metric.WithUnit("s"),
+		)
+		rt = limiter
+	}
+
+	// Short-circuit while the service is failing. Sits inside the retry
+	// layer so every attempt is counted and an open breaker stops retries.
+	if factory.breaker != nil {
+		rt = &circuitBreakerRoundTripper{base: rt, breaker: factory.breaker}
+	}
+
+	// Authenticate each attempt, so retries pick up refreshed credentials
+	if config.Auth != nil {
+		rt = &authRoundTripper{base: rt, auth: config.Auth}
+	}
+
 	// Wrap with retry logic
 	if config.MaxRetries > 0 {
+		retries, _ := app.otel.Meter("volt").Int64Counter("http.client.retries",
+			metric.WithDescription("Number of retried outbound HTTP requests"),
+			metric.WithUnit("{retry}"),
+		)
 		rt = &retryRoundTripper{
-			base:       rt,
-			maxRetries: config.MaxRetries,
-			minWait:    config.RetryWaitMin,
-			maxWait:    config.RetryWaitMax,
-			retryOn:    config.RetryOnStatus,
+			base:               rt,
+			maxRetries:         config.MaxRetries,
+			minWait:            config.RetryWaitMin,
+			maxWait:            config.RetryWaitMax,
+			retryOn:            config.RetryOnStatus,
+			retryNonIdempotent: config.RetryNonIdempotent,
+			retries:            retries,
+			attrs:              []attribute.KeyValue{attribute.String("service", name)},
 		}
 	}
 
@@ -291,10 +456,89 @@ func (r *Registry) createInstrumentedHTTPClient(app *App, config HTTPServiceConf
 		}
 	}
 
+	// Forward request identity and allowlisted inbound headers
+	if config.RequestIDHeader != "" || len(config.PropagateHeaders) > 0 {
+		rt = &propagationRoundTripper{
+			base:            rt,
+			requestIDHeader: config.RequestIDHeader,
+			headers:         config.PropagateHeaders,
+		}
+	}
+
+	// Resolve path-only request URLs against the base URL, so clients (such
+	// as generated ones) can be written against paths alone
+	if base, err := url.Parse(config.BaseURL); err == nil && base.Host != "" {
+		rt = &baseURLRoundTripper{base: rt, baseURL: base}
+	}
+
 	return &http.Client{
 		Transport: rt,
 		Timeout:   config.Timeout,
+	}, nil
+}
+
+// httpTransport returns the network transport for an HTTP service: the
+// configured Transport with the TLS settings applied, or a new one.
+func httpTransport(config HTTPServiceConfig) (http.RoundTripper, error) {
+	if config.Transport == nil {
+		return newHTTPTransport(config), nil
+	}
+	if config.TLSConfig == nil {
+		return config.Transport, nil
+	}
+
+	transport, ok := config.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("TLS settings need an *http.Transport, got %T", config.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = config.TLSConfig
+		return transport, nil
+	}
+	tlsConfig := transport.TLSClientConfig.Clone()
+	tlsConfig.Certificates = append(tlsConfig.Certificates, config.TLSConfig.Certificates...)
+	if config.TLSConfig.RootCAs != nil {
+		tlsConfig.RootCAs = config.TLSConfig.RootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// newHTTPTransport builds the network transport for an HTTP service.
+func newHTTPTransport(config HTTPServiceConfig) *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		TLSClientConfig:     config.TLSConfig,
+	}
+}
+
+// WrapHTTPTransport wraps the network transport of a registered HTTP
+// service, e.g. to fake or record its traffic in tests. The service's
+// factory, instrumentation and retry layers are unaffected. It must be
+// called before Initialize.
+func (r *Registry) WrapHTTPTransport(name string, wrap func(http.RoundTripper) http.RoundTripper) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factory, ok := r.httpServices[name]
+	if !ok {
+		return fmt.Errorf("HTTP service %q not registered", name)
+	}
+	if factory.instance != nil {
+		return fmt.Errorf("HTTP service %q already initialized", name)
+	}
+
+	base, err := httpTransport(factory.config)
+	if err != nil {
+		return fmt.Errorf("HTTP service %q: %w", name, err)
 	}
+	// The TLS settings are part of base now
+	factory.config.Transport = wrap(base)
+	factory.config.TLSConfig = nil
+	return nil
 }
 
 // --- Database Registration ---
@@ -304,11 +548,20 @@ type DatabaseConfig struct {
 	Driver string
 	DSN    string
 
+	// Value for the db.system span attribute. Derived from Driver when
+	// empty (e.g. "pgx" -> "postgresql").
+	System string
+
 	// Connection pool settings
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+
+	// The database is pinged by the health endpoint. A failed ping makes it
+	// unhealthy (503) unless HealthOptional is set.
+	HealthOptional bool
+	HealthTimeout  time.Duration // default: Config.Health.Timeout
 }
 
 // DefaultDatabaseConfig returns sensible defaults.
@@ -322,7 +575,8 @@ func DefaultDatabaseConfig() DatabaseConfig {
 }
 
 // RegisterDatabase registers a database connection.
-// Returns the instrumented *sql.DB directly.
+// Returns the instrumented *sql.DB directly: every query, exec and
+// transaction is traced and connection pool stats are exported as metrics.
 //
 // Example:
 //
@@ -364,9 +618,7 @@ func RegisterDatabaseService[T any](app *App, name string, config DatabaseConfig
 
 // createInstrumentedDB creates a database connection with OTEL instrumentation.
 func (r *Registry) createInstrumentedDB(ctx context.Context, app *App, config DatabaseConfig, name string) (*sql.DB, error) {
-	// Note: In production, you'd use otelsql here
-	// db, err := otelsql.Open(config.Driver, config.DSN)
-	db, err := sql.Open(config.Driver, config.DSN)
+	db, err := openInstrumentedDB(config.Driver, config.DSN, config.System, app.otel.Tracer("volt/database"))
 	if err != nil {
 		return nil, err
 	}
@@ -388,46 +640,6 @@ func (r *Registry) createInstrumentedDB(ctx context.Context, app *App, config Da
 
 // --- Round Trippers ---
 
-type retryRoundTripper struct {
-	base       http.RoundTripper
-	maxRetries int
-	minWait    time.Duration
-	maxWait    time.Duration
-	retryOn    []int
-}
-
-func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
-
-	for attempt := 0; attempt <= rt.maxRetries; attempt++ {
-		resp, err = rt.base.RoundTrip(req)
-
-		if err == nil && !rt.shouldRetry(resp.StatusCode) {
-			return resp, nil
-		}
-
-		if attempt < rt.maxRetries {
-			wait := rt.minWait * time.Duration(1<<attempt)
-			if wait > rt.maxWait {
-				wait = rt.maxWait
-			}
-			time.Sleep(wait)
-		}
-	}
-
-	return resp, err
-}
-
-func (rt *retryRoundTripper) shouldRetry(status int) bool {
-	for _, s := range rt.retryOn {
-		if status == s {
-			return true
-		}
-	}
-	return false
-}
-
 type headerRoundTripper struct {
 	base    http.RoundTripper
 	headers map[string]string
@@ -441,3 +653,30 @@ func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	}
 	return rt.base.RoundTrip(req)
 }
+
+// baseURLRoundTripper prefixes path-only request URLs with the service's
+// base URL, keeping any base path ("http://billing/api" + "/invoices").
+type baseURLRoundTripper struct {
+	base    http.RoundTripper
+	baseURL *url.URL
+}
+
+func (rt *baseURLRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "" {
+		return rt.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	out := req.Clone(req.Context())
+	u := *req.URL
+	u.Scheme = rt.baseURL.Scheme
+	u.Host = rt.baseURL.Host
+	u.User = rt.baseURL.User
+	u.Path = strings.TrimSuffix(rt.baseURL.Path, "/") + "/" + strings.TrimPrefix(req.URL.Path, "/")
+	if req.URL.RawPath != "" || rt.baseURL.RawPath != "" {
+		u.RawPath = strings.TrimSuffix(rt.baseURL.EscapedPath(), "/") + "/" + strings.TrimPrefix(req.URL.EscapedPath(), "/")
+	}
+	out.URL = &u
+	out.Host = ""
+	return rt.base.RoundTrip(out)
+}
@@ -3,6 +3,7 @@ package volt
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -73,6 +74,20 @@ func TestRegistry(t *testing.T) {
 		assertEqual(t, "second", order[1])
 		assertEqual(t, "first", order[2])
 	})
+
+	t.Run("Override replaces services and skips their initialization", func(t *testing.T) {
+		app := newTestApp()
+		app.registry.Register("cache", "real", nil)
+		RegisterDatabase(app, "primary", DatabaseConfig{Driver: "no-such-driver"})
+
+		app.registry.Override("cache", "fake")
+		app.registry.Override("primary", "fake-db")
+
+		assertNil(t, app.registry.Initialize(context.Background(), app))
+		assertEqual(t, "fake", app.registry.MustGet("cache").(string))
+		assertEqual(t, "fake-db", app.registry.MustGet("primary").(string))
+		assertEqual(t, 0, len(app.registry.HealthChecks()))
+	})
 }
 
 func TestHTTPServiceConfig(t *testing.T) {
@@ -129,85 +144,6 @@ func TestDatabaseConfig(t *testing.T) {
 	})
 }
 
-func TestRetryRoundTripper(t *testing.T) {
-	t.Run("retries on configured status codes", func(t *testing.T) {
-		attempts := 0
-		transport := &mockRoundTripper{
-			roundTripFn: func(req *http.Request) (*http.Response, error) {
-				attempts++
-				if attempts < 3 {
-					return &http.Response{StatusCode: 503}, nil
-				}
-				return &http.Response{StatusCode: 200}, nil
-			},
-		}
-
-		rt := &retryRoundTripper{
-			base:       transport,
-			maxRetries: 3,
-			minWait:    1 * time.Millisecond,
-			maxWait:    10 * time.Millisecond,
-			retryOn:    []int{503},
-		}
-
-		req, _ := http.NewRequest("GET", "http://test", nil)
-		resp, err := rt.RoundTrip(req)
-
-		assertNil(t, err)
-		assertEqual(t, 200, resp.StatusCode)
-		assertEqual(t, 3, attempts)
-	})
-
-	t.Run("does not retry on success", func(t *testing.T) {
-		attempts := 0
-		transport := &mockRoundTripper{
-			roundTripFn: func(req *http.Request) (*http.Response, error) {
-				attempts++
-				return &http.Response{StatusCode: 200}, nil
-			},
-		}
-
-		rt := &retryRoundTripper{
-			base:       transport,
-			maxRetries: 3,
-			minWait:    1 * time.Millisecond,
-			maxWait:    10 * time.Millisecond,
-			retryOn:    []int{503},
-		}
-
-		req, _ := http.NewRequest("GET", "http://test", nil)
-		resp, err := rt.RoundTrip(req)
-
-		assertNil(t, err)
-		assertEqual(t, 200, resp.StatusCode)
-		assertEqual(t, 1, attempts)
-	})
-
-	t.Run("stops after max retries", func(t *testing.T) {
-		attempts := 0
-		transport := &mockRoundTripper{
-			roundTripFn: func(req *http.Request) (*http.Response, error) {
-				attempts++
-				return &http.Response{StatusCode: 503}, nil
-			},
-		}
-
-		rt := &retryRoundTripper{
-			base:       transport,
-			maxRetries: 2,
-			minWait:    1 * time.Millisecond,
-			maxWait:    10 * time.Millisecond,
-			retryOn:    []int{503},
-		}
-
-		req, _ := http.NewRequest("GET", "http://test", nil)
-		resp, _ := rt.RoundTrip(req)
-
-		assertEqual(t, 503, resp.StatusCode)
-		assertEqual(t, 3, attempts) // initial + 2 retries
-	})
-}
-
 func TestHeaderRoundTripper(t *testing.T) {
 	t.Run("adds default headers", func(t *testing.T) {
 		var capturedReq *http.Request
@@ -259,6 +195,37 @@ func TestHeaderRoundTripper(t *testing.T) {
 	})
 }
 
+func TestBaseURLRoundTripper(t *testing.T) {
+	var captured *http.Request
+	base, _ := url.Parse("http://billing:8080/api/")
+	rt := &baseURLRoundTripper{
+		base: &mockRoundTripper{
+			roundTripFn: func(req *http.Request) (*http.Response, error) {
+				captured = req
+				return &http.Response{StatusCode: 200}, nil
+			},
+		},
+		baseURL: base,
+	}
+
+	t.Run("resolves path-only URLs", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/invoices/a%2Fb?page=2", nil)
+		_, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, "http://billing:8080/api/invoices/a%2Fb?page=2", captured.URL.String())
+		assertEqual(t, "", req.URL.Host)
+	})
+
+	t.Run("leaves absolute URLs alone", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://other/x", nil)
+		_, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, "http://other/x", captured.URL.String())
+	})
+}
+
 // =============================================================================
 // Mocks
 // =============================================================================
@@ -0,0 +1,180 @@
+package volt
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// --- Retry Transport ---
+
+// retryRoundTripper retries failed requests with full-jitter exponential
+// backoff. Only idempotent requests are retried unless retryNonIdempotent is
+// set, and only if their body can be replayed via GetBody. Waits end early
+// when the request context is done.
+type retryRoundTripper struct {
+	base       http.RoundTripper
+	maxRetries int
+	minWait    time.Duration
+	maxWait    time.Duration
+	retryOn    []int
+
+	// Retry POST, PATCH and other non-idempotent requests too
+	retryNonIdempotent bool
+
+	// Counts retries (optional)
+	retries metric.Int64Counter
+	attrs   []attribute.KeyValue
+}
+
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	retryable := rt.retryable(req)
+
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.base.RoundTrip(attemptReq)
+
+		event := []attribute.KeyValue{attribute.Int("http.request.resend_count", attempt)}
+		if err != nil {
+			event = append(event, attribute.String("error.message", err.Error()))
+		} else {
+			event = append(event, attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		span.AddEvent("http.attempt", trace.WithAttributes(event...))
+
+		if !retryable || attempt >= rt.maxRetries || ctx.Err() != nil || !rt.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait, ok := rt.backoff(attempt, resp)
+		if !ok {
+			// Server asked us to wait longer than we're willing to
+			return resp, err
+		}
+
+		// Rewind the body before discarding the response, so a failure here
+		// still hands the caller the last response
+		next, rerr := rewindRequest(req)
+		if rerr != nil {
+			return resp, err
+		}
+		if resp != nil {
+			drainBody(resp)
+		}
+
+		if rt.retries != nil {
+			rt.retries.Add(ctx, 1, metric.WithAttributes(rt.attrs...))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		attemptReq = next
+	}
+}
+
+// retryable reports whether req may be sent more than once.
+func (rt *retryRoundTripper) retryable(req *http.Request) bool {
+	if rt.maxRetries <= 0 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return rt.retryNonIdempotent || isIdempotent(req)
+}
+
+func (rt *retryRoundTripper) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		var open *CircuitOpenError
+		return !errors.As(err, &open)
+	}
+	return slices.Contains(rt.retryOn, resp.StatusCode)
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header on 429 and 503 responses takes precedence over the jittered
+// backoff; if it exceeds maxWait, ok is false and no retry should be made.
+func (rt *retryRoundTripper) backoff(attempt int, resp *http.Response) (wait time.Duration, ok bool) {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, found := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); found {
+			return d, rt.maxWait <= 0 || d <= rt.maxWait
+		}
+	}
+
+	// Full jitter: uniform in [0, min(maxWait, minWait * 2^attempt)]
+	ceiling := rt.minWait << min(attempt, 30)
+	if ceiling <= 0 || (rt.maxWait > 0 && ceiling > rt.maxWait) {
+		ceiling = rt.maxWait
+	}
+	if ceiling <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1)), true
+}
+
+// isIdempotent follows RFC 9110: safe methods plus PUT and DELETE. Requests
+// carrying an Idempotency-Key header are treated as idempotent too.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// rewindRequest returns a copy of req with a fresh body from GetBody.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// drainBody reads a bounded amount of the body so the connection can be
+// reused, then closes it.
+func drainBody(resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
+// parseRetryAfter parses a Retry-After value in delay-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,297 @@
+package volt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	t.Run("retries on configured status codes", func(t *testing.T) {
+		attempts := 0
+		transport := &mockRoundTripper{
+			roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts < 3 {
+					return &http.Response{StatusCode: 503}, nil
+				}
+				return &http.Response{StatusCode: 200}, nil
+			},
+		}
+
+		rt := &retryRoundTripper{
+			base:       transport,
+			maxRetries: 3,
+			minWait:    1 * time.Millisecond,
+			maxWait:    10 * time.Millisecond,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		resp, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, 200, resp.StatusCode)
+		assertEqual(t, 3, attempts)
+	})
+
+	t.Run("does not retry on success", func(t *testing.T) {
+		attempts := 0
+		transport := &mockRoundTripper{
+			roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{StatusCode: 200}, nil
+			},
+		}
+
+		rt := &retryRoundTripper{
+			base:       transport,
+			maxRetries: 3,
+			minWait:    1 * time.Millisecond,
+			maxWait:    10 * time.Millisecond,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		resp, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, 200, resp.StatusCode)
+		assertEqual(t, 1, attempts)
+	})
+
+	t.Run("stops after max retries", func(t *testing.T) {
+		attempts := 0
+		transport := &mockRoundTripper{
+			roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{StatusCode: 503}, nil
+			},
+		}
+
+		rt := &retryRoundTripper{
+			base:       transport,
+			maxRetries: 2,
+			minWait:    1 * time.Millisecond,
+			maxWait:    10 * time.Millisecond,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		resp, _ := rt.RoundTrip(req)
+
+		assertEqual(t, 503, resp.StatusCode)
+		assertEqual(t, 3, attempts) // initial + 2 retries
+	})
+	t.Run("does not retry non-idempotent methods by default", func(t *testing.T) {
+		attempts := 0
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{StatusCode: 503}, nil
+			}},
+			maxRetries: 3,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("POST", "http://test", strings.NewReader("payload"))
+		resp, _ := rt.RoundTrip(req)
+
+		assertEqual(t, 503, resp.StatusCode)
+		assertEqual(t, 1, attempts)
+
+		// An idempotency key makes the request safe to repeat
+		attempts = 0
+		req, _ = http.NewRequest("POST", "http://test", strings.NewReader("payload"))
+		req.Header.Set("Idempotency-Key", "abc")
+		rt.RoundTrip(req)
+		assertEqual(t, 4, attempts)
+	})
+
+	t.Run("replays the body when retrying opted-in methods", func(t *testing.T) {
+		var bodies []string
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				b, _ := io.ReadAll(req.Body)
+				bodies = append(bodies, string(b))
+				if len(bodies) < 3 {
+					return &http.Response{StatusCode: 502}, nil
+				}
+				return &http.Response{StatusCode: 201}, nil
+			}},
+			maxRetries:         3,
+			retryOn:            []int{502},
+			retryNonIdempotent: true,
+		}
+
+		req, _ := http.NewRequest("POST", "http://test", strings.NewReader("payload"))
+		resp, err := rt.RoundTrip(req)
+
+		assertNil(t, err)
+		assertEqual(t, 201, resp.StatusCode)
+		assertEqual(t, "payload,payload,payload", strings.Join(bodies, ","))
+	})
+
+	t.Run("does not retry bodies that cannot be replayed", func(t *testing.T) {
+		attempts := 0
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{StatusCode: 503}, nil
+			}},
+			maxRetries: 3,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("PUT", "http://test", io.NopCloser(strings.NewReader("stream")))
+		rt.RoundTrip(req)
+
+		assertEqual(t, 1, attempts)
+	})
+
+	t.Run("closes discarded response bodies", func(t *testing.T) {
+		var discarded []*trackingBody
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				body := &trackingBody{Reader: strings.NewReader("busy")}
+				discarded = append(discarded, body)
+				return &http.Response{StatusCode: 503, Body: body}, nil
+			}},
+			maxRetries: 2,
+			retryOn:    []int{503},
+		}
+
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		resp, _ := rt.RoundTrip(req)
+
+		assertEqual(t, 3, len(discarded))
+		assertTrue(t, discarded[0].closed)
+		assertTrue(t, discarded[1].closed)
+		assertTrue(t, !discarded[2].closed)
+		assertTrue(t, resp.Body == discarded[2])
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 503}, nil
+			}},
+			maxRetries: 3,
+			minWait:    time.Hour,
+			maxWait:    time.Hour,
+			retryOn:    []int{503},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://test", nil)
+
+		start := time.Now()
+		_, err := rt.RoundTrip(req)
+
+		assertTrue(t, errors.Is(err, context.DeadlineExceeded))
+		assertTrue(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		attempts := 0
+		retryAfter := "0"
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				header := http.Header{}
+				header.Set("Retry-After", retryAfter)
+				return &http.Response{StatusCode: 429, Header: header}, nil
+			}},
+			maxRetries: 2,
+			minWait:    time.Hour,
+			maxWait:    time.Second,
+			retryOn:    []int{429},
+		}
+
+		// Retry-After: 0 overrides the hour-long backoff
+		req, _ := http.NewRequest("GET", "http://test", nil)
+		rt.RoundTrip(req)
+		assertEqual(t, 3, attempts)
+
+		// Waits longer than maxWait return the response instead
+		attempts = 0
+		retryAfter = "120"
+		resp, err := rt.RoundTrip(req)
+		assertNil(t, err)
+		assertEqual(t, 429, resp.StatusCode)
+		assertEqual(t, 1, attempts)
+	})
+
+	t.Run("records attempts as span events and counts retries", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		reader := sdkmetric.NewManualReader()
+		counter, _ := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test").Int64Counter("http.client.retries")
+
+		attempts := 0
+		rt := &retryRoundTripper{
+			base: &mockRoundTripper{roundTripFn: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				if attempts == 1 {
+					return nil, errors.New("connection reset")
+				}
+				return &http.Response{StatusCode: 200}, nil
+			}},
+			maxRetries: 3,
+			retries:    counter,
+			attrs:      []attribute.KeyValue{attribute.String("service", "billing")},
+		}
+
+		ctx, span := tp.Tracer("test").Start(context.Background(), "caller")
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://test", nil)
+		_, err := rt.RoundTrip(req)
+		span.End()
+		assertNil(t, err)
+
+		events := recorder.Ended()[0].Events()
+		assertEqual(t, 2, len(events))
+		assertEqual(t, "http.attempt", events[0].Name)
+
+		var rm metricdata.ResourceMetrics
+		assertNil(t, reader.Collect(context.Background(), &rm))
+		sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+		assertEqual(t, int64(1), sum.DataPoints[0].Value)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("5", now)
+	assertTrue(t, ok)
+	assertEqual(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+	assertTrue(t, ok)
+	assertEqual(t, 90*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assertTrue(t, !ok)
+}
+
+// trackingBody records whether it was closed.
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
@@ -0,0 +1,262 @@
+package volttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bermos/volt"
+)
+
+// --- Record / Replay ---
+
+// RecordEnv forces cassettes to be re-recorded when set to "1" or "true".
+const RecordEnv = "VOLT_RECORD"
+
+// RecordHTTPService records or replays the traffic of the named service.
+//
+// If the cassette file exists, requests are answered from it and nothing
+// reaches the network; a request without a recorded answer fails the test.
+// If it doesn't exist (or VOLT_RECORD=1 is set), requests go to the real
+// service and the exchanges are written to the cassette when the test
+// passes. An empty path defaults to testdata/cassettes/<test>/<service>.json.
+//
+// Only the method, path, query and body of requests are recorded, with the
+// values of RedactedQueryParams replaced; request headers are not recorded.
+// Responses are recorded without Date, Content-Length and Set-Cookie, and
+// with the values of RedactedHeaders replaced. Credentials passed elsewhere,
+// e.g. in request or response bodies, must be kept out of testdata by the
+// test itself.
+func RecordHTTPService(t testing.TB, app *volt.App, name, path string) {
+	t.Helper()
+	if path == "" {
+		path = filepath.Join("testdata", "cassettes", cassetteName(t.Name()), cassetteName(name)+".json")
+	}
+
+	record := os.Getenv(RecordEnv) == "1" || os.Getenv(RecordEnv) == "true"
+	c := &cassette{t: t, name: name, path: path}
+	if !record {
+		err := c.load()
+		if errors.Is(err, fs.ErrNotExist) {
+			record = true
+		} else if err != nil {
+			t.Fatalf("volttest: loading cassette %s: %v", path, err)
+		}
+	}
+
+	err := app.Registry().WrapHTTPTransport(name, func(base http.RoundTripper) http.RoundTripper {
+		if record {
+			c.base = base
+			t.Cleanup(c.save)
+		}
+		return c
+	})
+	if err != nil {
+		t.Fatalf("volttest: recording %q: %v", name, err)
+	}
+}
+
+// RedactedQueryParams and RedactedHeaders list the request query parameters
+// and response headers whose values cassettes record as "REDACTED". Names
+// are matched case-insensitively; extend them for services that use other
+// names.
+var (
+	RedactedQueryParams = []string{"access_token", "api_key", "apikey", "client_secret", "key", "password", "sig", "signature", "token"}
+	RedactedHeaders     = []string{"Authorization", "Proxy-Authorization", "X-Access-Token", "X-Amz-Security-Token", "X-Api-Key", "X-Auth-Token"}
+)
+
+const redacted = "REDACTED"
+
+type cassette struct {
+	t    testing.TB
+	name string
+	path string
+	base http.RoundTripper // nil when replaying
+
+	mu           sync.Mutex
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+	used     bool
+}
+
+type recordedRequest struct {
+	Method string  `json:"method"`
+	URL    string  `json:"url"` // path and query
+	Body   payload `json:"body,omitzero"`
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   payload     `json:"body,omitzero"`
+}
+
+// payload stores JSON objects and arrays as JSON, so cassettes stay
+// readable and diffable, and anything else as a string.
+type payload []byte
+
+func (p payload) MarshalJSON() ([]byte, error) {
+	trimmed := bytes.TrimSpace(p)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, trimmed); err == nil {
+			return buf.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(p))
+}
+
+func (p *payload) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*p = payload(s)
+		return nil
+	}
+	*p = append(payload(nil), data...)
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if c.base != nil {
+		return c.record(req, body)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, it := range c.Interactions {
+		if it.used || it.Request.Method != req.Method || it.Request.URL != recordedURI(req.URL) {
+			continue
+		}
+		if !jsonEqual(it.Request.Body, body) {
+			continue
+		}
+		it.used = true
+		return newResponse(req, it.Response.Status, it.Response.Header, it.Response.Body), nil
+	}
+
+	c.t.Errorf("volttest: %s: no recorded response for %s %s in %s (re-record with %s=1)",
+		c.name, req.Method, recordedURI(req.URL), c.path, RecordEnv)
+	return newResponse(req, http.StatusNotImplemented, nil, []byte("volttest: request not in cassette")), nil
+}
+
+func (c *cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := c.base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	for _, h := range []string{"Date", "Content-Length", "Set-Cookie"} {
+		header.Del(h)
+	}
+	for _, h := range RedactedHeaders {
+		if header.Get(h) != "" {
+			header.Set(h, redacted)
+		}
+	}
+
+	c.mu.Lock()
+	c.Interactions = append(c.Interactions, &interaction{
+		Request:  recordedRequest{Method: req.Method, URL: recordedURI(req.URL), Body: body},
+		Response: recordedResponse{Status: resp.StatusCode, Header: header, Body: respBody},
+	})
+	c.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (c *cassette) load() error {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
+}
+
+// save writes the recorded interactions, unless the test failed.
+func (c *cassette) save() {
+	if c.t.Failed() {
+		c.t.Logf("volttest: test failed, not writing cassette %s", c.path)
+		return
+	}
+
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.path), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(c.path, append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		c.t.Errorf("volttest: writing cassette %s: %v", c.path, err)
+	}
+}
+
+// recordedURI returns the path and query of u as recorded, with the values
+// of RedactedQueryParams replaced.
+func recordedURI(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+	query := u.Query()
+	changed := false
+	for name, values := range query {
+		if !slices.ContainsFunc(RedactedQueryParams, func(p string) bool { return strings.EqualFold(p, name) }) {
+			continue
+		}
+		for i := range values {
+			values[i] = redacted
+		}
+		changed = true
+	}
+	if !changed {
+		return u.RequestURI()
+	}
+	out := *u
+	out.RawQuery = query.Encode()
+	return out.RequestURI()
+}
+
+// cassetteName makes a test or service name safe for use in a path.
+func cassetteName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r == '/':
+			return filepath.Separator
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,70 @@
+package volttest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bermos/volt"
+)
+
+func TestRecordHTTPService(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "billing.json")
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Auth-Token", "secret-session")
+		w.Write([]byte(`{"id": "` + strings.TrimPrefix(r.URL.Path, "/invoices/") + `"}`))
+	}))
+
+	t.Run("records when the cassette is missing", func(t *testing.T) {
+		app := newBillingApp(volt.WithHTTPHeaders(map[string]string{"Authorization": "Bearer secret"}))
+		RecordHTTPService(t, app, "billing", path)
+		client := initialize(t, app)
+		client.base = upstream.URL
+
+		body, status, err := client.invoice(ctx, "inv_1?api_key=secret-key")
+		assertNil(t, err)
+		assertEqual(t, 200, status)
+		assertEqual(t, `{"id": "inv_1"}`, body)
+	})
+	upstream.Close()
+
+	data, err := os.ReadFile(path)
+	assertNil(t, err)
+	assertTrue(t, strings.Contains(string(data), `"url": "/invoices/inv_1?api_key=REDACTED"`))
+	assertTrue(t, strings.Contains(string(data), `"REDACTED"`))
+	assertTrue(t, strings.Contains(string(data), `"id": "inv_1"`))
+	assertTrue(t, !strings.Contains(string(data), "secret"))
+
+	t.Run("replays without the network", func(t *testing.T) {
+		app := newBillingApp()
+		RecordHTTPService(t, app, "billing", path)
+		client := initialize(t, app)
+
+		body, status, err := client.invoice(ctx, "inv_1?api_key=other-key")
+		assertNil(t, err)
+		assertEqual(t, 200, status)
+		assertTrue(t, jsonEqual([]byte(`{"id":"inv_1"}`), []byte(body)))
+	})
+
+	t.Run("unrecorded requests fail the test", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		app := newBillingApp()
+		RecordHTTPService(rec, app, "billing", path)
+		client := initialize(t, app)
+
+		_, status, _ := client.invoice(ctx, "inv_2")
+		assertEqual(t, http.StatusNotImplemented, status)
+		assertEqual(t, 1, len(rec.errors))
+	})
+}
+
+func TestCassetteName(t *testing.T) {
+	assertEqual(t, filepath.Join("TestX", "sub_case"), cassetteName("TestX/sub case"))
+}
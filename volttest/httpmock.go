@@ -0,0 +1,343 @@
+package volttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+// --- HTTP Service Mock ---
+
+// HTTPMock is a programmable fake for the network transport of a registered
+// HTTP service. The service's factory, instrumentation, retries and circuit
+// breaker still run; only the network is replaced.
+//
+// Example:
+//
+//	billing := volttest.MockHTTPService(t, app, "billing")
+//	billing.On("GET", "/invoices/{id}").RespondJSON(200, Invoice{ID: "inv_1"})
+//	billing.On("POST", "/invoices").Respond(503, "").Times(1)
+//	billing.On("POST", "/invoices").Delay(50 * time.Millisecond).RespondJSON(201, Invoice{})
+type HTTPMock struct {
+	t    testing.TB
+	name string
+
+	mu     sync.Mutex
+	routes []*Route
+	calls  []Call
+}
+
+// Call is a request received by an HTTPMock.
+type Call struct {
+	Method string
+	URL    string // path and query
+	Header http.Header
+	Body   []byte
+}
+
+// MockHTTPService replaces the network transport of the named service with
+// an HTTPMock. It must be called before the app is initialized. Requests
+// that match no route fail the test and get a 501 response.
+func MockHTTPService(t testing.TB, app *volt.App, name string) *HTTPMock {
+	t.Helper()
+	m := &HTTPMock{t: t, name: name}
+	err := app.Registry().WrapHTTPTransport(name, func(http.RoundTripper) http.RoundTripper {
+		return m
+	})
+	if err != nil {
+		t.Fatalf("volttest: mocking %q: %v", name, err)
+	}
+	return m
+}
+
+// On adds a route for method ("" or "*" for any) and a path pattern.
+// Patterns match whole path segments: "{name}" matches one segment and a
+// trailing "{name...}" or "*" matches the rest. Path values are available
+// to Handle via r.PathValue. Routes are tried in the order they were added.
+func (m *HTTPMock) On(method, pattern string) *Route {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := &Route{method: strings.ToUpper(method), pattern: pattern, status: http.StatusOK, header: http.Header{}}
+	m.routes = append(m.routes, r)
+	return r
+}
+
+// Calls returns the requests received so far.
+func (m *HTTPMock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns how many received requests match method and pattern.
+func (m *HTTPMock) CallCount(method, pattern string) int {
+	r := &Route{method: strings.ToUpper(method), pattern: pattern}
+	n := 0
+	for _, c := range m.Calls() {
+		path, _, _ := strings.Cut(c.URL, "?")
+		if r.matchMethod(c.Method) {
+			if _, ok := matchPath(r.pattern, path); ok {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// RoundTrip implements http.RoundTripper.
+func (m *HTTPMock) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: req.Method, URL: req.URL.RequestURI(), Header: req.Header.Clone(), Body: body})
+	route, values := m.match(req, body)
+	m.mu.Unlock()
+
+	if route == nil {
+		m.t.Errorf("volttest: %s: unexpected request %s %s", m.name, req.Method, req.URL.RequestURI())
+		return newResponse(req, http.StatusNotImplemented, nil, []byte("volttest: no route matches the request")), nil
+	}
+	return route.serve(req, body, values)
+}
+
+// match returns the first route accepting req that has responses left.
+func (m *HTTPMock) match(req *http.Request, body []byte) (*Route, map[string]string) {
+	for _, r := range m.routes {
+		if r.times > 0 && r.hits >= r.times {
+			continue
+		}
+		if !r.matchMethod(req.Method) {
+			continue
+		}
+		values, ok := matchPath(r.pattern, req.URL.Path)
+		if !ok {
+			continue
+		}
+		if !r.matchRequest(req, body) {
+			continue
+		}
+		r.hits++
+		return r, values
+	}
+	return nil, nil
+}
+
+// Route is a request matcher with its canned response.
+type Route struct {
+	method   string
+	pattern  string
+	matchers []func(*http.Request, []byte) bool
+
+	status  int
+	header  http.Header
+	body    []byte
+	handler http.HandlerFunc
+	err     error
+	delay   time.Duration
+	times   int
+	hits    int
+}
+
+// WithQuery only matches requests with the given query parameter value.
+func (r *Route) WithQuery(key, value string) *Route {
+	return r.Match(func(req *http.Request) bool { return req.URL.Query().Get(key) == value })
+}
+
+// WithHeader only matches requests with the given header value.
+func (r *Route) WithHeader(key, value string) *Route {
+	return r.Match(func(req *http.Request) bool { return req.Header.Get(key) == value })
+}
+
+// WithJSONBody only matches requests whose JSON body equals v.
+func (r *Route) WithJSONBody(v any) *Route {
+	want, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("volttest: encoding expected body: %v", err))
+	}
+	r.matchers = append(r.matchers, func(_ *http.Request, body []byte) bool {
+		return jsonEqual(want, body)
+	})
+	return r
+}
+
+// Match only matches requests accepted by fn. The request body has been
+// read; use WithJSONBody to match on it.
+func (r *Route) Match(fn func(req *http.Request) bool) *Route {
+	r.matchers = append(r.matchers, func(req *http.Request, _ []byte) bool { return fn(req) })
+	return r
+}
+
+// Respond sets the response status and body.
+func (r *Route) Respond(status int, body string) *Route {
+	r.status, r.body = status, []byte(body)
+	return r
+}
+
+// RespondJSON sets the response status and encodes v as its JSON body.
+func (r *Route) RespondJSON(status int, v any) *Route {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("volttest: encoding response body: %v", err))
+	}
+	r.status, r.body = status, body
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Header sets a response header.
+func (r *Route) Header(key, value string) *Route {
+	r.header.Set(key, value)
+	return r
+}
+
+// Handle serves matching requests with h instead of a canned response.
+func (r *Route) Handle(h http.HandlerFunc) *Route {
+	r.handler = h
+	return r
+}
+
+// Fail makes matching requests fail with err, as a network error would.
+func (r *Route) Fail(err error) *Route {
+	r.err = err
+	return r
+}
+
+// Delay holds matching requests for d before responding. The wait ends
+// early with the context error if the request is canceled.
+func (r *Route) Delay(d time.Duration) *Route {
+	r.delay = d
+	return r
+}
+
+// Times limits the route to the first n matching requests, after which
+// later routes are tried. Use it to script sequences such as a failure
+// followed by a success.
+func (r *Route) Times(n int) *Route {
+	r.times = n
+	return r
+}
+
+func (r *Route) matchMethod(method string) bool {
+	return r.method == "" || r.method == "*" || r.method == method
+}
+
+func (r *Route) matchRequest(req *http.Request, body []byte) bool {
+	for _, match := range r.matchers {
+		if !match(req, body) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Route) serve(req *http.Request, body []byte, values map[string]string) (*http.Response, error) {
+	if r.delay > 0 {
+		timer := time.NewTimer(r.delay)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.handler == nil {
+		return newResponse(req, r.status, r.header, r.body), nil
+	}
+
+	served := req.Clone(req.Context())
+	served.Body = io.NopCloser(bytes.NewReader(body))
+	for name, value := range values {
+		served.SetPathValue(name, value)
+	}
+	rec := httptest.NewRecorder()
+	r.handler(rec, served)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// matchPath matches path against pattern, returning the path values.
+func matchPath(pattern, path string) (map[string]string, bool) {
+	values := map[string]string{}
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, seg := range patternSegs {
+		if seg == "*" || (strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "...}")) {
+			if seg != "*" {
+				values[strings.TrimSuffix(seg[1:], "...}")] = strings.Join(pathSegs[min(i, len(pathSegs)):], "/")
+			}
+			return values, i == len(patternSegs)-1
+		}
+		if i >= len(pathSegs) {
+			return nil, false
+		}
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if pathSegs[i] == "" {
+				return nil, false
+			}
+			values[seg[1:len(seg)-1]] = pathSegs[i]
+			continue
+		}
+		if seg != pathSegs[i] {
+			return nil, false
+		}
+	}
+	return values, len(patternSegs) == len(pathSegs)
+}
+
+// --- Helpers ---
+
+// readBody reads and closes the request body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// jsonEqual compares two JSON documents semantically.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
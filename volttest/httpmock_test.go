@@ -0,0 +1,216 @@
+package volttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+// billingClient stands in for a real client library built by a factory.
+type billingClient struct {
+	http *http.Client
+	base string
+}
+
+func (c *billingClient) invoice(ctx context.Context, id string) (string, int, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", c.base+"/invoices/"+id, nil)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp.StatusCode, nil
+}
+
+func newBillingApp(opts ...volt.HTTPServiceOption) *volt.App {
+	app := volt.New(volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	opts = append([]volt.HTTPServiceOption{volt.WithHTTPRetries(0, 0, 0)}, opts...)
+	volt.RegisterHTTPService(app, "billing", func(c *http.Client) *billingClient {
+		return &billingClient{http: c, base: "http://billing.internal"}
+	}, opts...)
+	return app
+}
+
+func initialize(t *testing.T, app *volt.App) *billingClient {
+	t.Helper()
+	ctx := context.Background()
+	if err := app.Registry().Initialize(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { app.Registry().Shutdown(ctx) })
+	return app.Registry().MustGet("billing").(*billingClient)
+}
+
+func TestMockHTTPService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("canned responses and path patterns", func(t *testing.T) {
+		app := newBillingApp()
+		mock := MockHTTPService(t, app, "billing")
+		mock.On("GET", "/invoices/{id}").WithQuery("expand", "lines").Respond(200, "expanded")
+		mock.On("GET", "/invoices/{id}").RespondJSON(200, map[string]string{"id": "inv_1"})
+		client := initialize(t, app)
+
+		body, status, err := client.invoice(ctx, "inv_1")
+		assertNil(t, err)
+		assertEqual(t, 200, status)
+		assertEqual(t, `{"id":"inv_1"}`, body)
+
+		body, _, _ = client.invoice(ctx, "inv_1?expand=lines")
+		assertEqual(t, "expanded", body)
+
+		assertEqual(t, 2, mock.CallCount("GET", "/invoices/*"))
+		assertEqual(t, "/invoices/inv_1?expand=lines", mock.Calls()[1].URL)
+	})
+
+	t.Run("handlers see path values and the body", func(t *testing.T) {
+		app := newBillingApp()
+		mock := MockHTTPService(t, app, "billing")
+		mock.On("", "/invoices/{id...}").Handle(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, "%s %s", r.Method, r.PathValue("id"))
+		})
+		client := initialize(t, app)
+
+		body, status, err := client.invoice(ctx, "2024/inv_7")
+		assertNil(t, err)
+		assertEqual(t, http.StatusAccepted, status)
+		assertEqual(t, "GET 2024/inv_7", body)
+	})
+
+	t.Run("sequences exercise the real retry layer", func(t *testing.T) {
+		app := newBillingApp(volt.WithHTTPRetries(2, 0, 0))
+		mock := MockHTTPService(t, app, "billing")
+		mock.On("GET", "/invoices/{id}").Respond(503, "").Times(1)
+		mock.On("GET", "/invoices/{id}").Respond(200, "ok")
+		client := initialize(t, app)
+
+		body, status, err := client.invoice(ctx, "inv_1")
+		assertNil(t, err)
+		assertEqual(t, 200, status)
+		assertEqual(t, "ok", body)
+		assertEqual(t, 2, len(mock.Calls()))
+	})
+
+	t.Run("error and latency injection", func(t *testing.T) {
+		app := newBillingApp()
+		mock := MockHTTPService(t, app, "billing")
+		mock.On("GET", "/invoices/broken").Fail(errors.New("connection reset"))
+		mock.On("GET", "/invoices/slow").Delay(time.Second)
+		client := initialize(t, app)
+
+		_, _, err := client.invoice(ctx, "broken")
+		assertTrue(t, err != nil && strings.Contains(err.Error(), "connection reset"))
+
+		timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, _, err = client.invoice(timeout, "slow")
+		assertTrue(t, errors.Is(err, context.DeadlineExceeded))
+		assertTrue(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("unexpected requests fail the test", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		app := newBillingApp()
+		MockHTTPService(rec, app, "billing")
+		client := initialize(t, app)
+
+		_, status, err := client.invoice(ctx, "inv_1")
+		assertNil(t, err)
+		assertEqual(t, http.StatusNotImplemented, status)
+		assertEqual(t, 1, len(rec.errors))
+		assertTrue(t, strings.Contains(rec.errors[0], "unexpected request GET /invoices/inv_1"))
+	})
+
+	t.Run("requires a registered, uninitialized service", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		app := newBillingApp()
+		initialize(t, app)
+
+		func() {
+			defer func() { recover() }()
+			MockHTTPService(rec, app, "billing")
+		}()
+		assertEqual(t, 1, len(rec.errors))
+		assertTrue(t, strings.Contains(rec.errors[0], "already initialized"))
+	})
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		match         bool
+		values        map[string]string
+	}{
+		{"/invoices", "/invoices", true, nil},
+		{"/invoices", "/invoices/1", false, nil},
+		{"/invoices/{id}", "/invoices/1", true, map[string]string{"id": "1"}},
+		{"/invoices/{id}", "/invoices/", false, nil},
+		{"/invoices/{id}/lines", "/invoices/1/lines", true, map[string]string{"id": "1"}},
+		{"/files/{path...}", "/files/a/b/c", true, map[string]string{"path": "a/b/c"}},
+		{"/files/*", "/files/a/b", true, nil},
+		{"/", "/", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			values, ok := matchPath(tt.pattern, tt.path)
+			assertEqual(t, tt.match, ok)
+			for k, v := range tt.values {
+				assertEqual(t, v, values[k])
+			}
+		})
+	}
+}
+
+// --- Helpers ---
+
+// recordingTB captures Errorf and Fatalf instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	mu     sync.Mutex
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	panic("fatal")
+}
+
+func assertEqual[T comparable](t *testing.T, expected, actual T) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func assertTrue(t *testing.T, condition bool) {
+	t.Helper()
+	if !condition {
+		t.Error("expected true, got false")
+	}
+}
+
+func assertNil(t *testing.T, v any) {
+	t.Helper()
+	if err, ok := v.(error); ok && err != nil {
+		t.Errorf("expected nil, got error: %v", err)
+	}
+}
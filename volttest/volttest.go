@@ -0,0 +1,314 @@
+// Package volttest provides helpers for testing Volt applications in
+// process: a harness that starts an app without binding a port, fakes for
+// registered HTTP services, and record/replay cassettes.
+//
+// Example:
+//
+//	func TestGreet(t *testing.T) {
+//	    app := newApp() // registers operations and services
+//	    h := volttest.Start(t, app, volttest.WithService("users", fakeUsers{}))
+//
+//	    resp := h.Get("/greet/ada").AssertStatus(200)
+//	    out := volttest.Decode[GreetOutput](t, resp)
+//	    // out.Body.Message == "Hello, ada!"
+//	}
+package volttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+// --- Harness ---
+
+// Harness runs an App in process for the duration of a test.
+type Harness struct {
+	t      testing.TB
+	app    *volt.App
+	client *http.Client
+}
+
+// Option configures a Harness.
+type Option func(*harnessConfig)
+
+type harnessConfig struct {
+	overrides map[string]any
+	timeout   time.Duration
+}
+
+// WithService replaces the registered service name with instance before the
+// app starts. Overridden HTTP services and databases are never initialized,
+// so no connections are attempted.
+func WithService(name string, instance any) Option {
+	return func(c *harnessConfig) {
+		c.overrides[name] = instance
+	}
+}
+
+// WithStartTimeout bounds how long Start waits for OnStart hooks and
+// service initialization (default: 10s). The context passed to them stays
+// valid until the test ends.
+func WithStartTimeout(d time.Duration) Option {
+	return func(c *harnessConfig) {
+		c.timeout = d
+	}
+}
+
+// Start runs the app's OnStart hooks and initializes its services, as Run
+// would, without binding a port or trapping signals. The app is shut down
+// when the test ends.
+func Start(t testing.TB, app *volt.App, opts ...Option) *Harness {
+	t.Helper()
+	config := harnessConfig{overrides: map[string]any{}, timeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	for name, instance := range config.overrides {
+		app.Registry().Override(name, instance)
+	}
+
+	// Hooks may keep the context for background work, so it lives until
+	// the test ends, after the app is shut down
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	started := make(chan error, 1)
+	go func() { started <- app.Start(ctx) }()
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Fatalf("volttest: starting app: %v", err)
+		}
+	case <-time.After(config.timeout):
+		t.Fatalf("volttest: app did not start within %s", config.timeout)
+	}
+
+	t.Cleanup(func() {
+		if err := app.Shutdown(context.Background()); err != nil {
+			t.Errorf("volttest: shutting down app: %v", err)
+		}
+	})
+
+	return &Harness{
+		t:      t,
+		app:    app,
+		client: &http.Client{Transport: handlerTransport{handler: app.Router()}},
+	}
+}
+
+// App returns the app under test.
+func (h *Harness) App() *volt.App {
+	return h.app
+}
+
+// Client returns an *http.Client that serves requests with the app's router
+// in process. Requests may use any host, e.g. "http://app/users"; pass it
+// to generated clients to call the app through its own API.
+func (h *Harness) Client() *http.Client {
+	return h.client
+}
+
+// RequestOption modifies a request before it is sent.
+type RequestOption func(*http.Request)
+
+// WithHeader sets a request header.
+func WithHeader(key, value string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// Get sends a GET request to path.
+func (h *Harness) Get(path string, opts ...RequestOption) *Response {
+	h.t.Helper()
+	return h.Do(http.MethodGet, path, nil, opts...)
+}
+
+// Delete sends a DELETE request to path.
+func (h *Harness) Delete(path string, opts ...RequestOption) *Response {
+	h.t.Helper()
+	return h.Do(http.MethodDelete, path, nil, opts...)
+}
+
+// PostJSON sends body encoded as JSON to path.
+func (h *Harness) PostJSON(path string, body any, opts ...RequestOption) *Response {
+	h.t.Helper()
+	return h.doJSON(http.MethodPost, path, body, opts...)
+}
+
+// PutJSON sends body encoded as JSON to path with PUT.
+func (h *Harness) PutJSON(path string, body any, opts ...RequestOption) *Response {
+	h.t.Helper()
+	return h.doJSON(http.MethodPut, path, body, opts...)
+}
+
+// PatchJSON sends body encoded as JSON to path with PATCH.
+func (h *Harness) PatchJSON(path string, body any, opts ...RequestOption) *Response {
+	h.t.Helper()
+	return h.doJSON(http.MethodPatch, path, body, opts...)
+}
+
+func (h *Harness) doJSON(method, path string, body any, opts ...RequestOption) *Response {
+	h.t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		h.t.Fatalf("volttest: encoding request body: %v", err)
+	}
+	opts = append([]RequestOption{WithHeader("Content-Type", "application/json")}, opts...)
+	return h.Do(method, path, bytes.NewReader(data), opts...)
+}
+
+// Do sends a request to path and reads the whole response.
+func (h *Harness) Do(method, path string, body io.Reader, opts ...RequestOption) *Response {
+	h.t.Helper()
+	req := httptest.NewRequest(method, path, body)
+	req.RequestURI = ""
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		h.t.Fatalf("volttest: %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("volttest: reading %s %s response: %v", method, path, err)
+	}
+	return &Response{Response: resp, Body: data, t: h.t}
+}
+
+// handlerTransport serves requests with an http.Handler in process.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (rt handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	served := req.Clone(req.Context())
+	served.RequestURI = req.URL.RequestURI()
+	served.RemoteAddr = "192.0.2.1:1234"
+	if served.Body == nil {
+		served.Body = http.NoBody
+	}
+
+	rec := httptest.NewRecorder()
+	rt.handler.ServeHTTP(rec, served)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// --- Responses ---
+
+// Response is a fully read response with assertion helpers. Assertions
+// report failures with t.Errorf and return the response for chaining.
+type Response struct {
+	*http.Response
+	Body []byte
+
+	t testing.TB
+}
+
+// AssertStatus checks the status code.
+func (r *Response) AssertStatus(status int) *Response {
+	r.t.Helper()
+	if r.StatusCode != status {
+		r.t.Errorf("volttest: %s %s: expected status %d, got %d: %s",
+			r.Request.Method, r.Request.URL.Path, status, r.StatusCode, r.Body)
+	}
+	return r
+}
+
+// AssertHeader checks a response header.
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header.Get(key); got != value {
+		r.t.Errorf("volttest: expected header %s %q, got %q", key, value, got)
+	}
+	return r
+}
+
+// AssertBody checks that the JSON body equals want. want may be an
+// operation output struct, in which case its Body field is compared. The
+// "$schema" link Huma adds to response bodies is ignored unless want has it.
+func (r *Response) AssertBody(want any) *Response {
+	r.t.Helper()
+	expected, err := json.Marshal(bodyOf(want))
+	if err != nil {
+		r.t.Fatalf("volttest: encoding expected body: %v", err)
+	}
+
+	var wantValue, gotValue any
+	_ = json.Unmarshal(expected, &wantValue)
+	if err := json.Unmarshal(r.Body, &gotValue); err != nil {
+		r.t.Errorf("volttest: response body is not JSON: %v: %s", err, r.Body)
+		return r
+	}
+	if wm, ok := wantValue.(map[string]any); ok {
+		if gm, ok := gotValue.(map[string]any); ok {
+			if _, has := wm["$schema"]; !has {
+				delete(gm, "$schema")
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(wantValue, gotValue) {
+		got, _ := json.Marshal(gotValue)
+		r.t.Errorf("volttest: body mismatch\nexpected: %s\n     got: %s", expected, got)
+	}
+	return r
+}
+
+// AssertBodyContains checks that the body contains substr.
+func (r *Response) AssertBodyContains(substr string) *Response {
+	r.t.Helper()
+	if !strings.Contains(string(r.Body), substr) {
+		r.t.Errorf("volttest: expected body to contain %q, got %s", substr, r.Body)
+	}
+	return r
+}
+
+// Decode decodes the JSON body into T. If T is an operation output struct
+// with a Body field, the body is decoded into that field.
+func Decode[T any](t testing.TB, r *Response) T {
+	t.Helper()
+	var out T
+	if err := json.Unmarshal(r.Body, bodyOf(&out)); err != nil {
+		t.Fatalf("volttest: decoding %T: %v: %s", out, err, r.Body)
+	}
+	return out
+}
+
+// bodyOf returns the Body field of an operation output struct (or a
+// pointer to it, for pointers), and v otherwise.
+func bodyOf(v any) any {
+	rv := reflect.ValueOf(v)
+	ptr := rv.Kind() == reflect.Pointer
+	if ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+	field, ok := rv.Type().FieldByName("Body")
+	if !ok || len(field.Index) != 1 || field.Tag.Get("json") != "" {
+		return v
+	}
+	body := rv.FieldByIndex(field.Index)
+	if ptr {
+		return body.Addr().Interface()
+	}
+	return body.Interface()
+}
@@ -0,0 +1,199 @@
+package volttest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bermos/volt"
+)
+
+type greeter interface {
+	Greet(name string) string
+}
+
+type politeGreeter struct{}
+
+func (politeGreeter) Greet(name string) string { return "Good day, " + name }
+
+type greetInput struct {
+	Name string `path:"name"`
+}
+
+type greetOutput struct {
+	Body struct {
+		Message string `json:"message"`
+	}
+}
+
+type echoInput struct {
+	Body struct {
+		Text string `json:"text" minLength:"1"`
+	}
+}
+
+type echoOutput struct {
+	Body struct {
+		Text string `json:"text"`
+	}
+}
+
+func newGreeterApp(events *[]string) *volt.App {
+	app := volt.New(volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	app.OnStart(func(ctx context.Context) error {
+		*events = append(*events, "start")
+		return nil
+	})
+	app.OnStop(func(ctx context.Context) error {
+		*events = append(*events, "stop")
+		return nil
+	})
+
+	app.Registry().Register("greeter", greeter(nil), nil)
+	volt.RegisterDatabase(app, "primary", volt.DatabaseConfig{Driver: "unregistered-driver"})
+
+	volt.Register(app, volt.Operation{
+		Method: "GET",
+		Path:   "/greet/{name}",
+	}, func(ctx context.Context, in *greetInput) (*greetOutput, error) {
+		out := &greetOutput{}
+		out.Body.Message = volt.Use[greeter](ctx, "greeter").Greet(in.Name)
+		return out, nil
+	})
+
+	volt.Register(app, volt.Operation{
+		Method: "POST",
+		Path:   "/echo",
+	}, func(ctx context.Context, in *echoInput) (*echoOutput, error) {
+		out := &echoOutput{}
+		out.Body.Text = in.Body.Text
+		return out, nil
+	})
+	return app
+}
+
+func TestHarness(t *testing.T) {
+	var events []string
+
+	t.Run("in-process requests", func(t *testing.T) {
+		app := newGreeterApp(&events)
+		h := Start(t, app,
+			WithService("greeter", politeGreeter{}),
+			WithService("primary", nil), // no real database in tests
+		)
+		assertTrue(t, app.Started())
+		assertEqual(t, "start", events[0])
+
+		want := greetOutput{}
+		want.Body.Message = "Good day, ada"
+		resp := h.Get("/greet/ada").AssertStatus(http.StatusOK).AssertBody(want)
+		assertEqual(t, "Good day, ada", Decode[greetOutput](t, resp).Body.Message)
+
+		resp = h.PostJSON("/echo", map[string]string{"text": "hi"}).AssertStatus(http.StatusOK)
+		assertEqual(t, "hi", Decode[echoOutput](t, resp).Body.Text)
+
+		h.PostJSON("/echo", map[string]string{"text": ""}).
+			AssertStatus(http.StatusUnprocessableEntity).
+			AssertBodyContains("body.text")
+
+		// The client also works for code that expects a real *http.Client
+		r, err := h.Client().Get("http://app/greet/bob")
+		assertNil(t, err)
+		assertEqual(t, http.StatusOK, r.StatusCode)
+		r.Body.Close()
+	})
+
+	// Cleanup of the subtest shut the app down
+	assertEqual(t, 2, len(events))
+	assertEqual(t, "stop", events[1])
+}
+
+func TestHarnessStartFailure(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	app := volt.New(volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	app.OnStart(func(ctx context.Context) error { return errors.New("migrations failed") })
+
+	func() {
+		defer func() { recover() }()
+		Start(rec, app)
+	}()
+	assertEqual(t, 1, len(rec.errors))
+	assertTrue(t, !app.Started())
+}
+
+func TestHarnessStartContext(t *testing.T) {
+	newApp := func(hook func(ctx context.Context) error) *volt.App {
+		app := volt.New(volt.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		app.OnStart(hook)
+		return app
+	}
+
+	t.Run("hooks keep their context until the test ends", func(t *testing.T) {
+		var hookCtx context.Context
+		t.Run("test", func(t *testing.T) {
+			Start(t, newApp(func(ctx context.Context) error {
+				hookCtx = ctx
+				return nil
+			}), WithStartTimeout(time.Millisecond))
+			time.Sleep(5 * time.Millisecond)
+			assertNil(t, hookCtx.Err())
+		})
+		assertEqual(t, context.Canceled, hookCtx.Err())
+	})
+
+	t.Run("the timeout bounds waiting for Start", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		release := make(chan struct{})
+		defer close(release)
+
+		func() {
+			defer func() { recover() }()
+			Start(rec, newApp(func(ctx context.Context) error {
+				<-release
+				return nil
+			}), WithStartTimeout(10*time.Millisecond))
+		}()
+		assertEqual(t, 1, len(rec.errors))
+		assertTrue(t, strings.Contains(rec.errors[0], "did not start within 10ms"))
+	})
+}
+
+func TestResponseAssertions(t *testing.T) {
+	rec := &recordingTB{TB: t}
+	resp := &Response{
+		Response: &http.Response{StatusCode: 404, Header: http.Header{"X-A": {"1"}}, Request: &http.Request{Method: "GET", URL: mustParse("/x")}},
+		Body:     []byte(`{"$schema": "http://app/schemas/X.json", "message": "hi"}`),
+		t:        rec,
+	}
+
+	resp.AssertStatus(404).AssertHeader("X-A", "1").AssertBody(map[string]string{"message": "hi"})
+	assertEqual(t, 0, len(rec.errors))
+
+	resp.AssertStatus(200).AssertBody(map[string]string{"message": "bye"})
+	assertEqual(t, 2, len(rec.errors))
+}
+
+func TestBodyOf(t *testing.T) {
+	out := greetOutput{}
+	out.Body.Message = "x"
+	assertEqual(t, "x", bodyOf(out).(struct {
+		Message string `json:"message"`
+	}).Message)
+
+	plain := map[string]int{"a": 1}
+	assertEqual(t, 1, bodyOf(plain).(map[string]int)["a"])
+}
+
+func mustParse(raw string) *url.URL {
+	u, err := url.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}